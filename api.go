@@ -16,8 +16,15 @@ const (
 	Error
 )
 
+// Info is the severity of informational messages that do not indicate a problem, such as the
+// references printed by "thrift-lint refs". Info messages never affect the exit status.
+const Info Severity = -1
+
 func (s Severity) String() string {
-	if s == Warning {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
 		return "warning"
 	}
 	return "error"
@@ -32,6 +39,9 @@ type Message struct {
 	Severity Severity
 	Object   interface{}
 	Message  string
+	// Path is the dotted path of the named AST nodes enclosing the checked node, eg.
+	// "UserService.getUser".
+	Path string
 }
 
 // Messages is the set of messages each check should return.
@@ -105,6 +115,8 @@ type Check interface {
 	//     func (s *parser.Struct, f *parser.Field) (messages Messages)
 	//
 	// Will match all each struct field, but not union fields.
+	//
+	// Checks that need to see every file in the project may also accept a *Project.
 	Checker() interface{}
 }
 
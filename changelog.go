@@ -0,0 +1,132 @@
+package thriftlint
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/alecthomas/go-thrift/parser"
+)
+
+// Changelog of the differences between two versions of a schema tree, grouped by definition.
+//
+// Unlike Compare, a Changelog also includes changes to annotations and doc comments.
+type Changelog struct {
+	Services []*ChangelogSection `json:"services"`
+	// Types are all other definitions: structs, unions, exceptions, enums, typedefs and constants.
+	Types []*ChangelogSection `json:"types"`
+}
+
+// ChangelogSection lists the changes to a single definition.
+type ChangelogSection struct {
+	// Definition is the qualified name of the definition, as in Change.
+	Definition string            `json:"definition"`
+	Kind       string            `json:"kind"`
+	Changes    []*ChangelogEntry `json:"changes"`
+}
+
+// ChangelogEntry is a single change to a definition.
+type ChangelogEntry struct {
+	// Change is "added", "removed" or "modified".
+	Change        string `json:"change"`
+	Compatibility string `json:"compatibility"`
+	Member        string `json:"member,omitempty"`
+	Message       string `json:"message"`
+	File          string `json:"file"`
+	Line          int    `json:"line"`
+}
+
+// ChangelogDirs parses the schema trees under oldDir and newDir and builds a Changelog of their
+// differences.
+func ChangelogDirs(oldDir, newDir string, includeDirs []string) (*Changelog, error) {
+	old, err := ParseDir(oldDir, includeDirs)
+	if err != nil {
+		return nil, err
+	}
+	newer, err := ParseDir(newDir, includeDirs)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := compareTrees(&schemaTree{oldDir, includeDirs, old}, &schemaTree{newDir, includeDirs, newer}, true, Full)
+	if err != nil {
+		return nil, err
+	}
+	return newChangelog(changes), nil
+}
+
+// NewChangelog builds a Changelog of the differences between two versions of a schema tree. The
+// arguments are as for Compare.
+func NewChangelog(oldRoot string, old map[string]*parser.Thrift, newRoot string, newer map[string]*parser.Thrift) (*Changelog, error) {
+	changes, err := compareTrees(&schemaTree{oldRoot, nil, old}, &schemaTree{newRoot, nil, newer}, true, Full)
+	if err != nil {
+		return nil, err
+	}
+	return newChangelog(changes), nil
+}
+
+// Group changes into a Changelog.
+func newChangelog(changes Changes) *Changelog {
+	changelog := &Changelog{Services: []*ChangelogSection{}, Types: []*ChangelogSection{}}
+	sections := map[string]*ChangelogSection{}
+	for _, change := range changes {
+		section := sections[change.Definition]
+		if section == nil {
+			section = &ChangelogSection{Definition: change.Definition, Kind: change.DefinitionKind.String()}
+			sections[change.Definition] = section
+			if change.DefinitionKind == ServiceKind {
+				changelog.Services = append(changelog.Services, section)
+			} else {
+				changelog.Types = append(changelog.Types, section)
+			}
+		}
+		section.Changes = append(section.Changes, &ChangelogEntry{
+			Change:        change.Kind.String(),
+			Compatibility: change.Compatibility.String(),
+			Member:        change.Member,
+			Message:       change.Message,
+			File:          change.File.Filename,
+			Line:          Pos(change.Object).Line,
+		})
+	}
+	return changelog
+}
+
+// WriteMarkdown writes the changelog as a Markdown document.
+func (c *Changelog) WriteMarkdown(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "# Schema changes\n"); err != nil {
+		return err
+	}
+	if len(c.Services) == 0 && len(c.Types) == 0 {
+		_, err := fmt.Fprintf(w, "\nNo changes.\n")
+		return err
+	}
+	groups := []struct {
+		title    string
+		sections []*ChangelogSection
+	}{
+		{"Services", c.Services},
+		{"Types", c.Types},
+	}
+	for _, group := range groups {
+		if len(group.sections) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "\n## %s\n", group.title); err != nil {
+			return err
+		}
+		for _, section := range group.sections {
+			if _, err := fmt.Fprintf(w, "\n### %s `%s`\n\n", section.Kind, section.Definition); err != nil {
+				return err
+			}
+			for _, entry := range section.Changes {
+				suffix := ""
+				if entry.Compatibility != Safe.String() {
+					suffix = fmt.Sprintf(" (**%s**)", entry.Compatibility)
+				}
+				if _, err := fmt.Fprintf(w, "- %s: %s%s\n", entry.Change, entry.Message, suffix); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package thriftlint
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangelog(t *testing.T) {
+	old := parseTestFiles(t, map[string]string{
+		"user.thrift": `
+// A user.
+struct User {
+  1: optional string id (go.tag = "id")
+  2: optional string name (deprecated = "true")
+}
+
+service UserService {
+  User getUser(1: string id)
+}
+`,
+	})
+	newer := parseTestFiles(t, map[string]string{
+		"user.thrift": `
+// A user of the system.
+struct User {
+  1: optional string id (go.tag = "user_id")
+  2: optional string name
+  3: optional string email (sensitive = "true")
+}
+
+enum Status { ACTIVE = 1 }
+
+service UserService {
+  User getUser(1: string id)
+  void deleteUser(1: string id)
+}
+`,
+	})
+	changelog, err := NewChangelog("/", old, "/", newer)
+	require.NoError(t, err)
+
+	w := &bytes.Buffer{}
+	require.NoError(t, changelog.WriteMarkdown(w))
+	require.Equal(t, "# Schema changes\n"+
+		"\n## Services\n"+
+		"\n### service `user.UserService`\n\n"+
+		"- added: method user.UserService.deleteUser was added\n"+
+		"\n## Types\n"+
+		"\n### enum `user.Status`\n\n"+
+		"- added: enum user.Status was added\n"+
+		"\n### struct `user.User`\n\n"+
+		"- modified: doc comment of struct user.User changed\n"+
+		"- modified: annotation go.tag of field 1 \"id\" of user.User changed from \"id\" to \"user_id\"\n"+
+		"- removed: annotation deprecated was removed from field 2 \"name\" of user.User\n"+
+		"- added: field 3 \"email\" of user.User was added\n",
+		w.String())
+
+	data, err := json.Marshal(changelog)
+	require.NoError(t, err)
+	decoded := &Changelog{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	require.Equal(t, changelog, decoded)
+	require.Equal(t, "email", decoded.Types[1].Changes[3].Member)
+
+	changelog, err = NewChangelog("/", old, "/", old)
+	require.NoError(t, err)
+	w.Reset()
+	require.NoError(t, changelog.WriteMarkdown(w))
+	require.Equal(t, "# Schema changes\n\nNo changes.\n", w.String())
+}
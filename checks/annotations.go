@@ -17,6 +17,31 @@ type AnnotationPattern struct {
 	Regex      string
 }
 
+// BuiltinAnnotationPatterns are the annotations understood by the builtin checks. They are always
+// accepted by CheckAnnotations, in addition to any patterns passed to it.
+var BuiltinAnnotationPatterns = []*AnnotationPattern{
+	{
+		Nodes:      []reflect.Type{thriftlint.StructType, thriftlint.EnumType},
+		Annotation: "thriftlint.reserved_ids",
+		Regex:      `\s*-?\d+(\s*-\s*-?\d+)?(\s*,\s*-?\d+(\s*-\s*-?\d+)?)*\s*`,
+	},
+	{
+		Nodes:      []reflect.Type{thriftlint.StructType, thriftlint.EnumType, thriftlint.ServiceType},
+		Annotation: "thriftlint.reserved_names",
+		Regex:      `\s*[A-Za-z_][A-Za-z0-9_]*(\s*,\s*[A-Za-z_][A-Za-z0-9_]*)*\s*`,
+	},
+	{
+		Nodes:      []reflect.Type{thriftlint.StructType, thriftlint.EnumType, thriftlint.TypedefType},
+		Annotation: "thriftlint.root",
+		Regex:      ``,
+	},
+	{
+		Nodes:      []reflect.Type{thriftlint.EnumValueType},
+		Annotation: "thriftlint.gap",
+		Regex:      ``,
+	},
+}
+
 type annotationsCheck struct {
 	patterns map[reflect.Type]map[string]string
 	checks   thriftlint.Checks
@@ -26,11 +51,14 @@ type annotationsCheck struct {
 //
 // All supported annotations must be represented.
 //
+// BuiltinAnnotationPatterns are always supported.
+//
 // NOTE: This should be the last check added in order to correctly validate the allowed values
 // for "nolint".
 func CheckAnnotations(patterns []*AnnotationPattern, checks thriftlint.Checks) thriftlint.Check {
 	patternsLUT := map[reflect.Type]map[string]string{}
-	for _, pattern := range patterns {
+	all := append(append([]*AnnotationPattern{}, BuiltinAnnotationPatterns...), patterns...)
+	for _, pattern := range all {
 		for _, node := range pattern.Nodes {
 			mapping, ok := patternsLUT[node]
 			if !ok {
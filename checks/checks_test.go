@@ -0,0 +1,149 @@
+package checks
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+// Directory that test sources are linted from. The files exist only in the linter overlay.
+const testDir = "/thriftlint-test"
+
+// Lint in-memory sources, keyed by file name, with checks. Only source is linted directly, along
+// with everything it includes.
+func lintMessages(t *testing.T, checks thriftlint.Checks, source string, sources map[string]string) thriftlint.Messages {
+	overlay := map[string][]byte{}
+	for name, content := range sources {
+		overlay[filepath.Join(testDir, name)] = []byte(content)
+	}
+	linter, err := thriftlint.New(checks, thriftlint.WithOverlay(overlay))
+	require.NoError(t, err)
+	messages, err := linter.Lint([]string{filepath.Join(testDir, source)})
+	require.NoError(t, err)
+	return messages
+}
+
+// Format a message as "<file>:<line>:<col>:<severity>: <message> (<check>)".
+func formatTestMessage(msg *thriftlint.Message) string {
+	pos := thriftlint.Pos(msg.Object)
+	return fmt.Sprintf("%s:%d:%d:%s: %s (%s)", filepath.Base(msg.File.Filename), pos.Line, pos.Col,
+		msg.Severity, msg.Message, msg.Checker)
+}
+
+// Lint as lintMessages, returning the formatted messages sorted.
+func lintTest(t *testing.T, checks thriftlint.Checks, source string, sources map[string]string) []string {
+	out := []string{}
+	for _, msg := range lintMessages(t, checks, source, sources) {
+		out = append(out, formatTestMessage(msg))
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Lint a single in-memory file named "test.thrift".
+func lintSource(t *testing.T, check thriftlint.Check, source string) []string {
+	return lintTest(t, thriftlint.Checks{check}, "test.thrift", map[string]string{"test.thrift": source})
+}
+
+// Every check, as configured by thrift-lint.
+func allChecks() thriftlint.Checks {
+	all := thriftlint.Checks{
+		CheckIndentation(),
+		CheckNames(nil, nil),
+		CheckOptional(),
+		CheckDefaultValuesPolicy(&DefaultsPolicy{Scalars: true}),
+		CheckEnumSequence(),
+		CheckEnumDuplicates(),
+		CheckEnumZero(nil),
+		CheckEnumPrefix(),
+		CheckMapKeys(),
+		CheckTypeReferences(),
+		CheckThrowsTypes(),
+		CheckExtendsTypes(),
+		CheckMethodOneway(),
+		CheckMethodThrows(),
+		CheckMethodArguments(),
+		CheckMethodInherited(),
+		CheckConstantReferences(),
+		CheckValues(),
+		CheckStructFieldOrder(),
+		CheckFieldIDDuplicates(),
+		CheckFieldIDPositive(),
+		CheckFieldIDRange(),
+		CheckReserved(),
+		CheckRecursion(),
+		CheckUnused(nil),
+		CheckIncludesUnused(),
+		CheckIncludesDuplicate(),
+		CheckIncludesCycle(),
+		CheckIncludesLayering(nil),
+		CheckNamespacesRequired(nil),
+		CheckNamespacesPattern(nil),
+		CheckNamespacesConsistent(nil),
+		CheckNamespacesPath(nil),
+		CheckNamespacesConflict(),
+		CheckDocsMissing(&DocsPolicy{Fields: true, EnumValues: true}),
+		CheckDocsStyle(&DocsPolicy{Fields: true, EnumValues: true}),
+		CheckDocsLinks(nil),
+	}
+	return append(all, CheckAnnotations(nil, all))
+}
+
+// Every check is called with the arguments it expects for every kind of node.
+func TestAllChecks(t *testing.T) {
+	messages := lintMessages(t, allChecks(), "test.thrift", map[string]string{
+		"test.thrift": `
+include "common.thrift"
+
+namespace go test
+
+typedef i64 Id
+
+const i32 LIMIT = 10
+
+enum Color {
+  RED = 0
+}
+
+struct Point {
+  1: optional i32 x = 1
+  2: optional map<string, list<Color>> colors
+}
+
+union Shape {
+  1: i32 radius = 1
+}
+
+exception Failure {
+  1: i32 code = 1
+}
+
+service Base {}
+
+service Points extends Base {
+  Point get(1: Id id) throws (1: Failure failure)
+  oneway void put(1: common.Common common)
+}
+`,
+		"common.thrift": "struct Common {}\n",
+	})
+	require.NotEmpty(t, messages)
+	defaults := []string{}
+	for _, msg := range messages {
+		if msg.Checker == "defaults" {
+			defaults = append(defaults, formatTestMessage(msg))
+		}
+	}
+	sort.Strings(defaults)
+	// The defaults check receives the parent of each field.
+	require.Equal(t, []string{
+		`test.thrift:15:3:warning: default values are not allowed on optional fields (defaults)`,
+		`test.thrift:20:3:warning: default values are not allowed in unions (defaults)`,
+		`test.thrift:24:3:warning: default values are not allowed in exceptions (defaults)`,
+	}, defaults)
+}
@@ -1,16 +1,121 @@
 package checks
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/UrbanCompass/thriftlint"
 
 	"github.com/alecthomas/go-thrift/parser"
 )
 
+// DefaultsPolicy configures which fields may have default values.
+type DefaultsPolicy struct {
+	// Scalars allows defaults on fields of builtin types, eg. i32 and string.
+	Scalars bool
+	// Enums allows defaults on enum fields.
+	Enums bool
+	// Containers allows defaults on list, set and map fields.
+	Containers bool
+	// Structs allows defaults on struct, union and exception fields.
+	Structs bool
+	// Optional allows defaults on optional fields. Otherwise only required and default fields may
+	// have defaults.
+	Optional bool
+	// Unions allows defaults on the fields of unions.
+	Unions bool
+	// Exceptions allows defaults on the fields of exceptions.
+	Exceptions bool
+}
+
+// ParseDefaultsPolicy parses a comma separated list of where defaults are allowed, from "scalar",
+// "enum", "container", "struct", "optional", "union" and "exception".
+func ParseDefaultsPolicy(allow string) (*DefaultsPolicy, error) {
+	policy := &DefaultsPolicy{}
+	for _, name := range strings.Split(allow, ",") {
+		switch strings.TrimSpace(name) {
+		case "scalar":
+			policy.Scalars = true
+		case "enum":
+			policy.Enums = true
+		case "container":
+			policy.Containers = true
+		case "struct":
+			policy.Structs = true
+		case "optional":
+			policy.Optional = true
+		case "union":
+			policy.Unions = true
+		case "exception":
+			policy.Exceptions = true
+		default:
+			return nil, fmt.Errorf("unknown default value policy %q, expected one of scalar, enum, container, struct, optional, union or exception", name)
+		}
+	}
+	return policy, nil
+}
+
+// Return why a default is not allowed on field, or "" if it is allowed.
+func (d *DefaultsPolicy) disallowed(file *parser.Thrift, parent interface{}, field *parser.Field) string {
+	if s, ok := parent.(*parser.Struct); ok {
+		if !d.Unions && file.Unions[s.Name] == s {
+			return "in unions"
+		}
+		if !d.Exceptions && file.Exceptions[s.Name] == s {
+			return "in exceptions"
+		}
+	}
+	if !d.Optional && field.Optional {
+		return "on optional fields"
+	}
+	resolved, err := thriftlint.ResolveType(field.Type, file)
+	if err != nil {
+		return ""
+	}
+	switch resolved.Kind {
+	case thriftlint.BuiltinKind:
+		if !d.Scalars {
+			return "on scalar fields"
+		}
+	case thriftlint.EnumKind:
+		if !d.Enums {
+			return "on enum fields"
+		}
+	case thriftlint.ContainerKind:
+		if !d.Containers {
+			return "on container fields"
+		}
+	case thriftlint.StructKind, thriftlint.UnionKind, thriftlint.ExceptionKind:
+		if !d.Structs {
+			return "on struct fields"
+		}
+	}
+	return ""
+}
+
 // CheckDefaultValues checks that default values are not provided.
 func CheckDefaultValues() thriftlint.Check {
-	return thriftlint.MakeCheck("defaults", func(field *parser.Field) (messages thriftlint.Messages) {
-		if field.Default != nil {
+	return CheckDefaultValuesPolicy(nil)
+}
+
+// CheckDefaultValuesPolicy checks that default values are only provided where allowed by policy,
+// and that they are valid for the type of their field. If policy is nil, default values are not
+// allowed at all.
+//
+// Mistyped defaults are reported with their own message, and the policy applies to them as well,
+// so disabling the "values" check does not hide defaults from this one.
+func CheckDefaultValuesPolicy(policy *DefaultsPolicy) thriftlint.Check {
+	return thriftlint.MakeCheck("defaults", func(file *parser.Thrift, parent interface{}, field *parser.Field) (messages thriftlint.Messages) {
+		if field.Default == nil {
+			return
+		}
+		if err := thriftlint.TypeCheckValue(field.Type, field.Default, file); err != nil {
+			messages.Warning(field, "default value of %s does not match its type: %s", field.Name, err)
+		}
+		if policy == nil {
 			messages.Warning(field, "default values are not allowed")
+		} else if reason := policy.disallowed(file, parent, field); reason != "" {
+			messages.Warning(field, "default values are not allowed %s", reason)
 		}
 		return
 	})
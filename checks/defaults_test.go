@@ -0,0 +1,92 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDefaultsPolicy(t *testing.T) {
+	policy, err := ParseDefaultsPolicy("scalar, enum,container,struct,optional,union,exception")
+	require.NoError(t, err)
+	require.Equal(t, &DefaultsPolicy{
+		Scalars: true, Enums: true, Containers: true, Structs: true, Optional: true, Unions: true, Exceptions: true,
+	}, policy)
+
+	policy, err = ParseDefaultsPolicy("scalar")
+	require.NoError(t, err)
+	require.Equal(t, &DefaultsPolicy{Scalars: true}, policy)
+
+	_, err = ParseDefaultsPolicy("scalar,structs")
+	require.Error(t, err)
+	_, err = ParseDefaultsPolicy("")
+	require.Error(t, err)
+}
+
+const defaultsTestSource = `
+enum Color {
+  RED = 0
+}
+
+struct Point {
+  1: i32 x
+}
+
+struct Struct {
+  1: i32 scalar = 1
+  2: Color enumeration = Color.RED
+  3: list<i32> container = [1]
+  4: Point point = {"x": 1}
+  5: optional i32 optional = 1
+  6: i32 mistyped = "one"
+  7: i32 none
+}
+
+union Union {
+  1: i32 scalar = 1
+}
+
+exception Exception {
+  1: i32 scalar = 1
+}
+`
+
+func TestCheckDefaultValues(t *testing.T) {
+	messages := lintSource(t, CheckDefaultValues(), defaultsTestSource)
+	require.Equal(t, []string{
+		`test.thrift:11:3:warning: default values are not allowed (defaults)`,
+		`test.thrift:12:3:warning: default values are not allowed (defaults)`,
+		`test.thrift:13:3:warning: default values are not allowed (defaults)`,
+		`test.thrift:14:3:warning: default values are not allowed (defaults)`,
+		`test.thrift:15:3:warning: default values are not allowed (defaults)`,
+		`test.thrift:16:3:warning: default value of mistyped does not match its type: expected i32, got string "one" (defaults)`,
+		`test.thrift:16:3:warning: default values are not allowed (defaults)`,
+		`test.thrift:21:3:warning: default values are not allowed (defaults)`,
+		`test.thrift:25:3:warning: default values are not allowed (defaults)`,
+	}, messages)
+
+	policy, err := ParseDefaultsPolicy("scalar,enum")
+	require.NoError(t, err)
+	messages = lintSource(t, CheckDefaultValuesPolicy(policy), defaultsTestSource)
+	require.Equal(t, []string{
+		`test.thrift:13:3:warning: default values are not allowed on container fields (defaults)`,
+		`test.thrift:14:3:warning: default values are not allowed on struct fields (defaults)`,
+		`test.thrift:15:3:warning: default values are not allowed on optional fields (defaults)`,
+		`test.thrift:16:3:warning: default value of mistyped does not match its type: expected i32, got string "one" (defaults)`,
+		`test.thrift:21:3:warning: default values are not allowed in unions (defaults)`,
+		`test.thrift:25:3:warning: default values are not allowed in exceptions (defaults)`,
+	}, messages)
+
+	policy, err = ParseDefaultsPolicy("container,struct,optional,union,exception")
+	require.NoError(t, err)
+	messages = lintSource(t, CheckDefaultValuesPolicy(policy), defaultsTestSource)
+	require.Equal(t, []string{
+		`test.thrift:11:3:warning: default values are not allowed on scalar fields (defaults)`,
+		`test.thrift:12:3:warning: default values are not allowed on enum fields (defaults)`,
+		`test.thrift:15:3:warning: default values are not allowed on scalar fields (defaults)`,
+		`test.thrift:16:3:warning: default value of mistyped does not match its type: expected i32, got string "one" (defaults)`,
+		`test.thrift:16:3:warning: default values are not allowed on scalar fields (defaults)`,
+		`test.thrift:21:3:warning: default values are not allowed on scalar fields (defaults)`,
+		`test.thrift:25:3:warning: default values are not allowed on scalar fields (defaults)`,
+	}, messages)
+}
@@ -0,0 +1,179 @@
+package checks
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/alecthomas/go-thrift/parser"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+// DocsPolicy configures which nodes require doc comments, and the style of those comments.
+//
+// Services, methods, structs, unions, exceptions and enums always require doc comments when
+// "docs.missing" is enabled.
+type DocsPolicy struct {
+	// Fields requires doc comments on the fields of structs, unions and exceptions.
+	Fields bool
+	// EnumValues requires doc comments on enum values.
+	EnumValues bool
+	// StartWithName requires doc comments to start with the name of the documented node.
+	StartWithName bool
+	// Sentence requires doc comments to be complete sentences, starting with an upper case letter
+	// or the name of the documented node, and ending with a period.
+	Sentence bool
+	// MinLength is the minimum length of doc comments, in characters.
+	MinLength int
+}
+
+// Return the name of a node that requires a doc comment, or "" if it does not.
+func (d *DocsPolicy) documented(parent, self interface{}) string {
+	switch node := self.(type) {
+	case *parser.Service:
+		return node.Name
+	case *parser.Method:
+		return node.Name
+	case *parser.Struct:
+		return node.Name
+	case *parser.Enum:
+		return node.Name
+	case *parser.Field:
+		if _, ok := parent.(*parser.Struct); ok && d.Fields {
+			return node.Name
+		}
+	case *parser.EnumValue:
+		if d.EnumValues {
+			return node.Name
+		}
+	}
+	return ""
+}
+
+// CheckDocsMissing checks that nodes required by policy have doc comments.
+func CheckDocsMissing(policy *DocsPolicy) thriftlint.Check {
+	return thriftlint.MakeCheck("docs.missing", func(parent, self interface{}) (messages thriftlint.Messages) {
+		name := policy.documented(parent, self)
+		if name != "" && len(thriftlint.Comment(self)) == 0 {
+			messages.Warning(self, "%s should have a doc comment", name)
+		}
+		return
+	})
+}
+
+// CheckDocsStyle checks that doc comments follow the style rules of policy.
+func CheckDocsStyle(policy *DocsPolicy) thriftlint.Check {
+	return thriftlint.MakeCheck("docs.style", func(parent, self interface{}) (messages thriftlint.Messages) {
+		name := policy.documented(parent, self)
+		if name == "" {
+			return
+		}
+		comment := strings.Join(thriftlint.Comment(self), " ")
+		if comment == "" {
+			return
+		}
+		if policy.StartWithName && !startsWithWord(comment, name) {
+			messages.Warning(self, "doc comment for %s should start with %q", name, name)
+		}
+		if policy.Sentence {
+			first := []rune(comment)[0]
+			if !unicode.IsUpper(first) && !startsWithWord(comment, name) {
+				messages.Warning(self, "doc comment for %s should start with an upper case letter", name)
+			}
+			if !strings.HasSuffix(comment, ".") {
+				messages.Warning(self, "doc comment for %s should end with a period", name)
+			}
+		}
+		if policy.MinLength > 0 && len(comment) < policy.MinLength {
+			messages.Warning(self, "doc comment for %s should be at least %d characters long", name, policy.MinLength)
+		}
+		return
+	})
+}
+
+func startsWithWord(s, word string) bool {
+	if !strings.HasPrefix(s, word) {
+		return false
+	}
+	rest := []rune(s[len(word):])
+	return len(rest) == 0 || !(unicode.IsLetter(rest[0]) || unicode.IsDigit(rest[0]) || rest[0] == '_')
+}
+
+// DocsFileCoverage is the number of nodes in a file that have doc comments, out of the nodes that
+// require them.
+type DocsFileCoverage struct {
+	File       *parser.Thrift
+	Documented int
+	Total      int
+}
+
+// Percent of nodes that are documented. A file with nothing to document is fully covered.
+func (d *DocsFileCoverage) Percent() float64 {
+	if d.Total == 0 {
+		return 100
+	}
+	return 100 * float64(d.Documented) / float64(d.Total)
+}
+
+// DocsCoverage calculates the documentation coverage of each file, ordered by filename, counting
+// the nodes that require doc comments under policy.
+func DocsCoverage(files map[string]*parser.Thrift, policy *DocsPolicy) []*DocsFileCoverage {
+	out := []*DocsFileCoverage{}
+	for _, file := range files {
+		coverage := &DocsFileCoverage{File: file}
+		count := func(parent, self interface{}) {
+			if policy.documented(parent, self) == "" {
+				return
+			}
+			coverage.Total++
+			if len(thriftlint.Comment(self)) > 0 {
+				coverage.Documented++
+			}
+		}
+		for _, service := range file.Services {
+			count(file, service)
+			for _, method := range service.Methods {
+				count(service, method)
+			}
+		}
+		for _, structs := range []map[string]*parser.Struct{file.Structs, file.Unions, file.Exceptions} {
+			for _, s := range structs {
+				count(file, s)
+				for _, field := range s.Fields {
+					count(s, field)
+				}
+			}
+		}
+		for _, enum := range file.Enums {
+			count(file, enum)
+			for _, value := range enum.Values {
+				count(enum, value)
+			}
+		}
+		out = append(out, coverage)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].File.Filename < out[j].File.Filename })
+	return out
+}
+
+// CheckDocsLinks checks that references to other definitions in doc comments resolve, including
+// references to members of the form "Type.member".
+//
+// Each of patterns is a regular expression whose first group is the referenced symbol, as created
+// by thriftlint.ParseDocLinkPattern. If patterns is empty, thriftlint.DefaultDocLinkPatterns are
+// used.
+func CheckDocsLinks(patterns []*regexp.Regexp) thriftlint.Check {
+	if len(patterns) == 0 {
+		patterns = thriftlint.DefaultDocLinkPatterns
+	}
+	return thriftlint.MakeCheck("docs.links", func(project *thriftlint.Project, file *parser.Thrift, self interface{}) (messages thriftlint.Messages) {
+		for _, link := range thriftlint.DocLinks(project.Source(file), self, patterns) {
+			if thriftlint.ResolveMember(link.Symbol, file) == nil {
+				messages.Warning(link, "doc comment refers to unknown definition %q", link.Symbol)
+			}
+		}
+		return
+	})
+}
@@ -0,0 +1,105 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/alecthomas/go-thrift/parser"
+	"github.com/stretchr/testify/require"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+const docsTestSource = `
+// User is a registered user.
+struct User {
+  // The ID of the user.
+  1: string id
+  2: string name
+}
+
+union Undocumented {
+  1: string a
+}
+
+// short
+enum Color {
+  RED = 0
+}
+
+// Users manages users
+service Users {
+  // Get a user.
+  User get(1: string id)
+  void delete(1: string id)
+}
+`
+
+func TestCheckDocsMissing(t *testing.T) {
+	messages := lintSource(t, CheckDocsMissing(&DocsPolicy{}), docsTestSource)
+	require.Equal(t, []string{
+		`test.thrift:22:3:warning: delete should have a doc comment (docs.missing)`,
+		`test.thrift:9:7:warning: Undocumented should have a doc comment (docs.missing)`,
+	}, messages)
+
+	messages = lintSource(t, CheckDocsMissing(&DocsPolicy{Fields: true, EnumValues: true}), docsTestSource)
+	require.Equal(t, []string{
+		`test.thrift:10:3:warning: a should have a doc comment (docs.missing)`,
+		`test.thrift:15:3:warning: RED should have a doc comment (docs.missing)`,
+		`test.thrift:22:3:warning: delete should have a doc comment (docs.missing)`,
+		`test.thrift:6:3:warning: name should have a doc comment (docs.missing)`,
+		`test.thrift:9:7:warning: Undocumented should have a doc comment (docs.missing)`,
+	}, messages)
+}
+
+func TestCheckDocsStyle(t *testing.T) {
+	policy := &DocsPolicy{Fields: true, StartWithName: true, Sentence: true, MinLength: 12}
+	messages := lintSource(t, CheckDocsStyle(policy), docsTestSource)
+	require.Equal(t, []string{
+		`test.thrift:13:1:warning: doc comment for Color should be at least 12 characters long (docs.style)`,
+		`test.thrift:13:1:warning: doc comment for Color should end with a period (docs.style)`,
+		`test.thrift:13:1:warning: doc comment for Color should start with "Color" (docs.style)`,
+		`test.thrift:13:1:warning: doc comment for Color should start with an upper case letter (docs.style)`,
+		`test.thrift:18:1:warning: doc comment for Users should end with a period (docs.style)`,
+		`test.thrift:20:0:warning: doc comment for get should be at least 12 characters long (docs.style)`,
+		`test.thrift:20:0:warning: doc comment for get should start with "get" (docs.style)`,
+		`test.thrift:4:3:warning: doc comment for id should start with "id" (docs.style)`,
+	}, messages)
+}
+
+func TestDocsCoverage(t *testing.T) {
+	files, err := thriftlint.ParseWithOverlay(nil, []string{"/thriftlint-test/test.thrift"},
+		map[string][]byte{"/thriftlint-test/test.thrift": []byte(docsTestSource)})
+	require.NoError(t, err)
+	coverage := DocsCoverage(files, &DocsPolicy{Fields: true})
+	require.Equal(t, 1, len(coverage))
+	require.Equal(t, 5, coverage[0].Documented)
+	require.Equal(t, 9, coverage[0].Total)
+	require.InDelta(t, 55.6, coverage[0].Percent(), 0.1)
+	require.Equal(t, 100.0, (&DocsFileCoverage{File: &parser.Thrift{}}).Percent())
+}
+
+func TestCheckDocsLinks(t *testing.T) {
+	messages := lintTest(t, thriftlint.Checks{CheckDocsLinks(nil)}, "test.thrift", map[string]string{
+		"test.thrift": `
+include "common.thrift"
+
+// A [common.User] with a [Profile], or [Missing]. See [Docs](http://example.com).
+// See Users.get, and Users.missing
+struct Account {
+  // The [Account.id] of a [common.Missing].
+  1: string id
+}
+
+struct Profile {}
+
+service Users {
+  void get()
+}
+`,
+		"common.thrift": "struct User {}\n",
+	})
+	require.Equal(t, []string{
+		`test.thrift:4:42:warning: doc comment refers to unknown definition "Missing" (docs.links)`,
+		`test.thrift:7:29:warning: doc comment refers to unknown definition "common.Missing" (docs.links)`,
+	}, messages)
+}
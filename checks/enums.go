@@ -2,27 +2,118 @@ package checks
 
 import (
 	"sort"
+	"strings"
 
 	"github.com/UrbanCompass/thriftlint"
 
 	"github.com/alecthomas/go-thrift/parser"
 )
 
+// GapAnnotation allows an enum value to skip values after the previous one, eg.
+// "RETIRED = 10 (thriftlint.gap)".
+const GapAnnotation = "thriftlint.gap"
+
+// Enum values ordered by value, then by position, then by name.
+func sortedEnumValues(e *parser.Enum) []*parser.EnumValue {
+	values := []*parser.EnumValue{}
+	for _, v := range e.Values {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Value != values[j].Value {
+			return values[i].Value < values[j].Value
+		}
+		if values[i].Pos.Line != values[j].Pos.Line {
+			return values[i].Pos.Line < values[j].Pos.Line
+		}
+		if values[i].Pos.Col != values[j].Pos.Col {
+			return values[i].Pos.Col < values[j].Pos.Col
+		}
+		return values[i].Name < values[j].Name
+	})
+	return values
+}
+
 // CheckEnumSequence checks that enums start with 0 and increment sequentially.
+//
+// Values annotated with GapAnnotation may skip values. Duplicate values are reported by
+// CheckEnumDuplicates.
 func CheckEnumSequence() thriftlint.Check {
-	return thriftlint.MakeCheck("enum", func(e *parser.Enum) (messages thriftlint.Messages) {
-		values := []int{}
-		for _, v := range e.Values {
-			values = append(values, v.Value)
-		}
-		sort.Sort(sort.IntSlice(values))
-		for i := 0; i < len(values); i++ {
-			if values[i] != i {
-				messages.Warning(e,
-					"enum values for %s do not start at 0 and increase monotonically", e.Name)
-				break
+	return thriftlint.MakeCheck("enum.sequence", func(e *parser.Enum) (messages thriftlint.Messages) {
+		expected := 0
+		for i, v := range sortedEnumValues(e) {
+			if i > 0 && v.Value == expected-1 {
+				continue
+			}
+			if v.Value != expected && !thriftlint.AnnotationExists(v, GapAnnotation) {
+				messages.Warning(v, "%s.%s is %d, expected %d (annotate with %s to allow gaps)", e.Name, v.Name,
+					v.Value, expected, GapAnnotation)
+			}
+			expected = v.Value + 1
+		}
+		return
+	})
+}
+
+// CheckEnumDuplicates checks that enum values do not share the same numeric value.
+func CheckEnumDuplicates() thriftlint.Check {
+	return thriftlint.MakeCheck("enum.duplicate", func(e *parser.Enum) (messages thriftlint.Messages) {
+		values := sortedEnumValues(e)
+		for i := 1; i < len(values); i++ {
+			first := values[i-1]
+			for j := i - 1; j > 0 && values[j-1].Value == first.Value; j-- {
+				first = values[j-1]
+			}
+			if values[i].Value == first.Value {
+				messages.Error(values[i], "%s.%s has the same value (%d) as %s", e.Name, values[i].Name,
+					values[i].Value, first.Name)
 			}
 		}
 		return
 	})
 }
+
+// CheckEnumZero checks that the 0 value of every enum is a sentinel, named one of names, eg.
+// "UNKNOWN" or "UNSPECIFIED". The name may be prefixed with the enum name in UPPER_SNAKE_CASE, eg.
+// "COLOR_UNKNOWN".
+//
+// If names is empty the check is disabled.
+func CheckEnumZero(names []string) thriftlint.Check {
+	return thriftlint.MakeCheck("enum.zero", func(e *parser.Enum) (messages thriftlint.Messages) {
+		if len(names) == 0 {
+			return
+		}
+		expected := strings.Join(names, " or ")
+		for _, v := range sortedEnumValues(e) {
+			if v.Value != 0 {
+				continue
+			}
+			prefix := enumValuePrefix(e)
+			for _, name := range names {
+				if v.Name == name || v.Name == prefix+name {
+					return
+				}
+			}
+			messages.Warning(v, "%s.%s has value 0, which should be reserved for %s", e.Name, v.Name, expected)
+			return
+		}
+		messages.Warning(e, "enum %s should have a value 0 named %s", e.Name, expected)
+		return
+	})
+}
+
+// CheckEnumPrefix checks that enum value names are prefixed with the enum name in
+// UPPER_SNAKE_CASE, eg. "COLOR_RED" in enum "Color".
+func CheckEnumPrefix() thriftlint.Check {
+	return thriftlint.MakeCheck("enum.prefix", func(e *parser.Enum, v *parser.EnumValue) (messages thriftlint.Messages) {
+		prefix := enumValuePrefix(e)
+		if !strings.HasPrefix(v.Name, prefix) || v.Name == prefix {
+			messages.Warning(v, "%s.%s should be prefixed with %q", e.Name, v.Name, prefix)
+		}
+		return
+	})
+}
+
+func enumValuePrefix(e *parser.Enum) string {
+	return thriftlint.UpperSnakeCase(e.Name) + "_"
+}
@@ -0,0 +1,73 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/alecthomas/go-thrift/parser"
+	"github.com/stretchr/testify/require"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+const enumsTestSource = `
+enum Color {
+  COLOR_UNKNOWN = 0
+  COLOR_RED = 1
+  COLOR_GREEN = 3
+  COLOR_BLUE = 3
+  COLOR_RETIRED = 10 (thriftlint.gap)
+  PURPLE = 11
+}
+
+enum Size {
+  SMALL = 1
+  LARGE = 2
+}
+
+enum Shape {
+  CIRCLE = 0
+}
+`
+
+func TestCheckEnums(t *testing.T) {
+	checks := thriftlint.Checks{CheckEnumSequence(), CheckEnumDuplicates(), CheckEnumPrefix()}
+	messages := lintTest(t, checks, "test.thrift", map[string]string{"test.thrift": enumsTestSource})
+	require.Equal(t, []string{
+		`test.thrift:12:3:warning: Size.SMALL is 1, expected 0 (annotate with thriftlint.gap to allow gaps) (enum.sequence)`,
+		`test.thrift:12:3:warning: Size.SMALL should be prefixed with "SIZE_" (enum.prefix)`,
+		`test.thrift:13:0:warning: Size.LARGE should be prefixed with "SIZE_" (enum.prefix)`,
+		`test.thrift:17:3:warning: Shape.CIRCLE should be prefixed with "SHAPE_" (enum.prefix)`,
+		`test.thrift:5:0:warning: Color.COLOR_GREEN is 3, expected 2 (annotate with thriftlint.gap to allow gaps) (enum.sequence)`,
+		`test.thrift:6:0:error: Color.COLOR_BLUE has the same value (3) as COLOR_GREEN (enum.duplicate)`,
+		`test.thrift:8:0:warning: Color.PURPLE should be prefixed with "COLOR_" (enum.prefix)`,
+	}, messages)
+}
+
+func TestCheckEnumZero(t *testing.T) {
+	require.Equal(t, []string{}, lintSource(t, CheckEnumZero(nil), enumsTestSource))
+	require.Equal(t, []string{
+		`test.thrift:11:1:warning: enum Size should have a value 0 named UNKNOWN or UNSPECIFIED (enum.zero)`,
+		`test.thrift:17:3:warning: Shape.CIRCLE has value 0, which should be reserved for UNKNOWN or UNSPECIFIED (enum.zero)`,
+	}, lintSource(t, CheckEnumZero([]string{"UNKNOWN", "UNSPECIFIED"}), enumsTestSource))
+}
+
+func TestSortedEnumValues(t *testing.T) {
+	value := func(name string, value, line, col int) *parser.EnumValue {
+		return &parser.EnumValue{Name: name, Value: value, Pos: parser.Pos{Line: line, Col: col}}
+	}
+	e := &parser.Enum{Values: map[string]*parser.EnumValue{}}
+	for _, v := range []*parser.EnumValue{
+		value("D", 1, 1, 1),
+		value("C", 0, 2, 5),
+		value("B", 0, 2, 1),
+		value("A", 0, 2, 1),
+		value("E", 0, 1, 9),
+	} {
+		e.Values[v.Name] = v
+	}
+	names := []string{}
+	for _, v := range sortedEnumValues(e) {
+		names = append(names, v.Name)
+	}
+	require.Equal(t, []string{"E", "A", "B", "C", "D"}, names)
+}
@@ -0,0 +1,71 @@
+package checks
+
+import (
+	"math"
+
+	"github.com/alecthomas/go-thrift/parser"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+// Field lists of a node that have independent field IDs: the fields of a struct, union or
+// exception, or the arguments and throws clause of a method.
+func fieldLists(self interface{}) [][]*parser.Field {
+	switch node := self.(type) {
+	case *parser.Struct:
+		return [][]*parser.Field{node.Fields}
+	case *parser.Method:
+		return [][]*parser.Field{node.Arguments, node.Exceptions}
+	}
+	return nil
+}
+
+// CheckFieldIDDuplicates checks that field IDs are unique within structs, unions, exceptions,
+// method arguments and throws clauses.
+//
+// Note that implicit (auto-assigned) field IDs are rejected by the parser, so every field has an
+// explicit ID.
+func CheckFieldIDDuplicates() thriftlint.Check {
+	return thriftlint.MakeCheck("field.id.duplicate", func(self interface{}) (messages thriftlint.Messages) {
+		for _, fields := range fieldLists(self) {
+			seen := map[int]*parser.Field{}
+			for _, f := range fields {
+				if prev, ok := seen[f.ID]; ok {
+					messages.Error(f, "field %q has the same ID %d as field %q at %d:%d", f.Name, f.ID,
+						prev.Name, prev.Pos.Line, prev.Pos.Col)
+					continue
+				}
+				seen[f.ID] = f
+			}
+		}
+		return
+	})
+}
+
+// CheckFieldIDPositive checks that field IDs are greater than zero.
+func CheckFieldIDPositive() thriftlint.Check {
+	return thriftlint.MakeCheck("field.id.positive", func(self interface{}) (messages thriftlint.Messages) {
+		for _, fields := range fieldLists(self) {
+			for _, f := range fields {
+				if f.ID <= 0 && f.ID >= math.MinInt16 {
+					messages.Error(f, "field %q has non-positive ID %d", f.Name, f.ID)
+				}
+			}
+		}
+		return
+	})
+}
+
+// CheckFieldIDRange checks that field IDs fit in the i16 used to encode them on the wire.
+func CheckFieldIDRange() thriftlint.Check {
+	return thriftlint.MakeCheck("field.id.range", func(self interface{}) (messages thriftlint.Messages) {
+		for _, fields := range fieldLists(self) {
+			for _, f := range fields {
+				if f.ID > math.MaxInt16 || f.ID < math.MinInt16 {
+					messages.Error(f, "field %q has ID %d outside the i16 range", f.Name, f.ID)
+				}
+			}
+		}
+		return
+	})
+}
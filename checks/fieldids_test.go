@@ -0,0 +1,46 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+func TestCheckFieldIDs(t *testing.T) {
+	checks := thriftlint.Checks{CheckFieldIDDuplicates(), CheckFieldIDPositive(), CheckFieldIDRange()}
+	messages := lintTest(t, checks, "test.thrift", map[string]string{"test.thrift": `
+struct Struct {
+  1: optional string a
+  1: optional string b
+  0: optional string c
+}
+
+union Union {
+  2: string a
+  2: string b
+  40000: string c
+}
+
+exception Exception {
+  3: string a
+  3: string b
+}
+
+service Service {
+  void method(1: string a, 1: string b, 0: string c) throws (1: Exception a, 1: Exception b)
+  void other(1: string a) throws (1: Exception e)
+}
+`})
+	require.Equal(t, []string{
+		`test.thrift:10:3:error: field "b" has the same ID 2 as field "a" at 9:3 (field.id.duplicate)`,
+		`test.thrift:11:3:error: field "c" has ID 40000 outside the i16 range (field.id.range)`,
+		`test.thrift:16:3:error: field "b" has the same ID 3 as field "a" at 15:3 (field.id.duplicate)`,
+		`test.thrift:20:28:error: field "b" has the same ID 1 as field "a" at 20:15 (field.id.duplicate)`,
+		`test.thrift:20:41:error: field "c" has non-positive ID 0 (field.id.positive)`,
+		`test.thrift:20:78:error: field "b" has the same ID 1 as field "a" at 20:62 (field.id.duplicate)`,
+		`test.thrift:4:3:error: field "b" has the same ID 1 as field "a" at 3:3 (field.id.duplicate)`,
+		`test.thrift:5:3:error: field "c" has non-positive ID 0 (field.id.positive)`,
+	}, messages)
+}
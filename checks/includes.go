@@ -0,0 +1,155 @@
+package checks
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/alecthomas/go-thrift/parser"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+// CheckIncludesUnused checks for included files that are never referred to by a type, constant
+// value or "extends" in the including file.
+func CheckIncludesUnused() thriftlint.Check {
+	return thriftlint.MakeCheck("include.unused", func(project *thriftlint.Project, file *parser.Thrift) (messages thriftlint.Messages) {
+		used := usedIncludes(file)
+		reported := map[string]bool{}
+		for _, include := range thriftlint.Includes(file, project.Source(file)) {
+			if used[include.Name] || reported[include.Name] {
+				continue
+			}
+			reported[include.Name] = true
+			messages.Warning(include, "included file %q is not used", include.Path)
+		}
+		return
+	})
+}
+
+// CheckIncludesDuplicate checks for files that are included more than once, either by repeated
+// include statements or under different names, and for different files included under the same
+// name, of which only one can be referred to.
+func CheckIncludesDuplicate() thriftlint.Check {
+	return thriftlint.MakeCheck("include.duplicate", func(project *thriftlint.Project, file *parser.Thrift) (messages thriftlint.Messages) {
+		seen := map[string]*thriftlint.Include{}
+		names := map[string]*thriftlint.Include{}
+		for _, include := range thriftlint.Includes(file, project.Source(file)) {
+			path := project.IncludePath(file, include)
+			if prev, ok := seen[path]; ok {
+				if prev.Name == include.Name {
+					messages.Warning(include, "%q is already included at line %d", include.Path, prev.Pos.Line)
+				} else {
+					messages.Warning(include, "%q is the same file as %q, included at line %d", include.Path,
+						prev.Path, prev.Pos.Line)
+				}
+				continue
+			}
+			seen[path] = include
+			if prev, ok := names[include.Name]; ok {
+				messages.Warning(include, "%q has the same name %q as %q, included at line %d", include.Path,
+					include.Name, prev.Path, prev.Pos.Line)
+				continue
+			}
+			names[include.Name] = include
+		}
+		return
+	})
+}
+
+type includeCyclesKey struct{}
+
+// CheckIncludesCycle checks for files that include each other, directly or indirectly.
+//
+// Each cycle is reported on the include statement in each file of the cycle that leads to the next
+// file, with the full chain of files.
+func CheckIncludesCycle() thriftlint.Check {
+	return thriftlint.MakeCheck("include.cycle", func(project *thriftlint.Project, file *parser.Thrift) (messages thriftlint.Messages) {
+		cycles := project.Value(includeCyclesKey{}, func() interface{} {
+			return thriftlint.IncludeGraph(project.Files).Cycles()
+		}).([][]string)
+		for _, cycle := range cycles {
+			for i, path := range cycle[:len(cycle)-1] {
+				if path != file.Filename {
+					continue
+				}
+				// Rotate the cycle to start at this file.
+				chain := append(append([]string{}, cycle[i:]...), cycle[1:i+1]...)
+				names := []string{}
+				for _, path := range chain {
+					names = append(names, filepath.Base(path))
+				}
+				var object interface{} = file
+				for _, include := range thriftlint.Includes(file, project.Source(file)) {
+					if project.IncludePath(file, include) == chain[1] {
+						object = include
+						break
+					}
+				}
+				messages.Error(object, "include cycle %s", strings.Join(names, " -> "))
+			}
+		}
+		return
+	})
+}
+
+// Collect the include names that qualify any symbol referenced in file.
+func usedIncludes(file *parser.Thrift) map[string]bool {
+	used := map[string]bool{}
+	use := func(symbol string) {
+		if i := strings.Index(symbol, "."); i > 0 {
+			used[symbol[:i]] = true
+		}
+	}
+	var useType func(t *parser.Type)
+	useType = func(t *parser.Type) {
+		if t == nil {
+			return
+		}
+		use(t.Name)
+		useType(t.KeyType)
+		useType(t.ValueType)
+	}
+	var useValue func(v interface{})
+	useValue = func(v interface{}) {
+		switch v := v.(type) {
+		case parser.Identifier:
+			use(string(v))
+		case []interface{}:
+			for _, e := range v {
+				useValue(e)
+			}
+		case []parser.KeyValue:
+			for _, kv := range v {
+				useValue(kv.Key)
+				useValue(kv.Value)
+			}
+		}
+	}
+	useFields := func(fields []*parser.Field) {
+		for _, f := range fields {
+			useType(f.Type)
+			useValue(f.Default)
+		}
+	}
+	for _, t := range file.Typedefs {
+		useType(t.Type)
+	}
+	for _, c := range file.Constants {
+		useType(c.Type)
+		useValue(c.Value)
+	}
+	for _, structs := range []map[string]*parser.Struct{file.Structs, file.Unions, file.Exceptions} {
+		for _, s := range structs {
+			useFields(s.Fields)
+		}
+	}
+	for _, s := range file.Services {
+		use(s.Extends)
+		for _, m := range s.Methods {
+			useType(m.ReturnType)
+			useFields(m.Arguments)
+			useFields(m.Exceptions)
+		}
+	}
+	return used
+}
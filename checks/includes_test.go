@@ -0,0 +1,54 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+func TestCheckIncludes(t *testing.T) {
+	checks := thriftlint.Checks{CheckIncludesUnused(), CheckIncludesDuplicate()}
+	messages := lintTest(t, checks, "test.thrift", map[string]string{
+		"test.thrift": `
+include "a/common.thrift"
+include "b/common.thrift"
+include "./a/common.thrift"
+include "a/user.thrift"
+include "a/other.thrift"
+/*
+include "b/unused.thrift"
+*/
+
+struct Request {
+  1: optional common.Common common
+  2: optional other.Other other
+}
+`,
+		"a/common.thrift": "struct Common {}\n",
+		"a/user.thrift":   "struct User {}\n",
+		"a/other.thrift":  "struct Other {}\n",
+		"b/common.thrift": "struct Common {}\n",
+		"b/unused.thrift": "struct Unused {}\n",
+	})
+	require.Equal(t, []string{
+		`test.thrift:3:1:warning: "b/common.thrift" has the same name "common" as "a/common.thrift", included at line 2 (include.duplicate)`,
+		`test.thrift:4:1:warning: "./a/common.thrift" is already included at line 2 (include.duplicate)`,
+		`test.thrift:5:1:warning: included file "a/user.thrift" is not used (include.unused)`,
+	}, messages)
+}
+
+func TestCheckIncludesCycle(t *testing.T) {
+	messages := lintTest(t, thriftlint.Checks{CheckIncludesCycle()}, "a.thrift", map[string]string{
+		"a.thrift": "include \"b.thrift\"\ninclude \"c.thrift\"\n",
+		"b.thrift": "include \"a.thrift\"\n",
+		"c.thrift": "include \"a.thrift\"\n",
+	})
+	require.Equal(t, []string{
+		`a.thrift:1:1:error: include cycle a.thrift -> b.thrift -> a.thrift (include.cycle)`,
+		`a.thrift:2:1:error: include cycle a.thrift -> c.thrift -> a.thrift (include.cycle)`,
+		`b.thrift:1:1:error: include cycle b.thrift -> a.thrift -> b.thrift (include.cycle)`,
+		`c.thrift:1:1:error: include cycle c.thrift -> a.thrift -> c.thrift (include.cycle)`,
+	}, messages)
+}
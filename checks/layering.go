@@ -0,0 +1,153 @@
+package checks
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/alecthomas/go-thrift/parser"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+// LayeringRule restricts which files may include which other files.
+//
+// From and To are selectors. A selector of the form "namespace:PATTERN" or
+// "namespace:SCOPE=PATTERN" matches files with a namespace, for any or the given scope, matching
+// the glob PATTERN. eg. "namespace:go=*.payments". Any other selector is a glob matched against
+// the trailing path components of a file, where "**" matches any number of directories. eg.
+// "common/**" matches every file under a "common" directory.
+type LayeringRule struct {
+	// Allow rules permit files matching From to include only files matching To, or matching the To of
+	// another allow rule with a matching From. Deny rules forbid files matching From from including
+	// files matching To.
+	Allow bool
+	From  string
+	To    string
+}
+
+// ParseLayeringRule parses a rule of the form "FROM -> TO".
+func ParseLayeringRule(allow bool, rule string) (*LayeringRule, error) {
+	parts := strings.Split(rule, "->")
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+		return nil, fmt.Errorf("invalid layering rule %q, expected FROM -> TO", rule)
+	}
+	out := &LayeringRule{Allow: allow, From: strings.TrimSpace(parts[0]), To: strings.TrimSpace(parts[1])}
+	for _, selector := range []string{out.From, out.To} {
+		if err := checkSelector(selector); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (l *LayeringRule) String() string {
+	kind := "deny"
+	if l.Allow {
+		kind = "allow"
+	}
+	return fmt.Sprintf("%s %s -> %s", kind, l.From, l.To)
+}
+
+// CheckIncludesLayering checks include statements against layering rules, eg. that files under
+// "common/" never include files under "services/".
+func CheckIncludesLayering(rules []*LayeringRule) thriftlint.Check {
+	selectors := map[string]func(file *parser.Thrift) bool{}
+	for _, rule := range rules {
+		selectors[rule.From] = newSelector(rule.From)
+		selectors[rule.To] = newSelector(rule.To)
+	}
+	return thriftlint.MakeCheck("include.layering", func(project *thriftlint.Project, file *parser.Thrift) (messages thriftlint.Messages) {
+		allowed := []*LayeringRule{}
+		for _, rule := range rules {
+			if rule.Allow && selectors[rule.From](file) {
+				allowed = append(allowed, rule)
+			}
+		}
+		for _, include := range thriftlint.Includes(file, project.Source(file)) {
+			included := project.Files[project.IncludePath(file, include)]
+			if included == nil {
+				continue
+			}
+			for _, rule := range rules {
+				if !rule.Allow && selectors[rule.From](file) && selectors[rule.To](included) {
+					messages.Error(include, "include of %q violates layering rule %q", include.Path, rule)
+				}
+			}
+			if len(allowed) == 0 {
+				continue
+			}
+			ok := false
+			names := []string{}
+			for _, rule := range allowed {
+				ok = ok || selectors[rule.To](included)
+				names = append(names, fmt.Sprintf("%q", rule))
+			}
+			if !ok {
+				messages.Error(include, "include of %q is not allowed by layering rule %s", include.Path,
+					strings.Join(names, ", "))
+			}
+		}
+		return
+	})
+}
+
+const namespaceSelector = "namespace:"
+
+// Split a namespace selector into its optional scope and pattern.
+func splitNamespaceSelector(selector string) (scope, pattern string) {
+	pattern = strings.TrimPrefix(selector, namespaceSelector)
+	if i := strings.Index(pattern, "="); i >= 0 {
+		return pattern[:i], pattern[i+1:]
+	}
+	return "", pattern
+}
+
+func checkSelector(selector string) error {
+	if !strings.HasPrefix(selector, namespaceSelector) {
+		return nil
+	}
+	_, pattern := splitNamespaceSelector(selector)
+	_, err := filepath.Match(pattern, "")
+	return err
+}
+
+// Create a function matching files against a selector.
+func newSelector(selector string) func(file *parser.Thrift) bool {
+	if !strings.HasPrefix(selector, namespaceSelector) {
+		re := regexp.MustCompile(pathGlobToRegex(selector))
+		return func(file *parser.Thrift) bool { return re.MatchString(filepath.ToSlash(file.Filename)) }
+	}
+	scope, pattern := splitNamespaceSelector(selector)
+	return func(file *parser.Thrift) bool {
+		for namespaceScope, namespace := range file.Namespaces {
+			if scope != "" && scope != namespaceScope {
+				continue
+			}
+			if ok, _ := filepath.Match(pattern, namespace); ok {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Convert a path glob to a regex matching trailing path components.
+func pathGlobToRegex(glob string) string {
+	out := "(^|/)"
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; {
+		case c == '*' && i+1 < len(glob) && glob[i+1] == '*':
+			out += ".*"
+			i++
+		case c == '*':
+			out += "[^/]*"
+		case c == '?':
+			out += "[^/]"
+		default:
+			out += regexp.QuoteMeta(string(c))
+		}
+	}
+	return out + "$"
+}
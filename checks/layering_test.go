@@ -0,0 +1,83 @@
+package checks
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+func TestParseLayeringRule(t *testing.T) {
+	rule, err := ParseLayeringRule(true, " common/** ->namespace:go=acme.* ")
+	require.NoError(t, err)
+	require.Equal(t, &LayeringRule{Allow: true, From: "common/**", To: "namespace:go=acme.*"}, rule)
+	require.Equal(t, "allow common/** -> namespace:go=acme.*", rule.String())
+
+	for _, invalid := range []string{"common/**", "-> services/**", "a -> b -> c", "a -> namespace:[", "a ->  "} {
+		_, err := ParseLayeringRule(false, invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestPathGlobToRegex(t *testing.T) {
+	tests := []struct {
+		glob    string
+		path    string
+		matches bool
+	}{
+		{"common/**", "/idl/common/user.thrift", true},
+		{"common/**", "/idl/common/sub/user.thrift", true},
+		{"common/**", "/idl/uncommon/user.thrift", false},
+		{"services/*.thrift", "/idl/services/user.thrift", true},
+		{"services/*.thrift", "/idl/services/sub/user.thrift", false},
+		{"user?.thrift", "/idl/user1.thrift", true},
+		{"user?.thrift", "/idl/user.thrift", false},
+		{"user?.thrift", "/idl/user/.thrift", false},
+		{"a.thrift", "/idl/a.thrift", true},
+		{"a.thrift", "/idl/ba.thrift", false},
+		{"a.thrift", "/idl/axthrift", false},
+	}
+	for _, test := range tests {
+		re := regexp.MustCompile(pathGlobToRegex(test.glob))
+		require.Equal(t, test.matches, re.MatchString(test.path), "%s %s", test.glob, test.path)
+	}
+}
+
+func TestCheckIncludesLayering(t *testing.T) {
+	rules := []*LayeringRule{}
+	for _, rule := range []struct {
+		allow bool
+		rule  string
+	}{
+		{false, "common/** -> services/**"},
+		{true, "services/** -> common/**"},
+		{true, "services/** -> namespace:go=acme.shared"},
+		{false, "services/** -> common/internal.thrift"},
+	} {
+		parsed, err := ParseLayeringRule(rule.allow, rule.rule)
+		require.NoError(t, err)
+		rules = append(rules, parsed)
+	}
+	messages := lintTest(t, thriftlint.Checks{CheckIncludesLayering(rules)}, "services/service.thrift", map[string]string{
+		"services/service.thrift": `
+include "../common/user.thrift"
+include "../common/internal.thrift"
+include "../shared/shared.thrift"
+include "../other/external.thrift"
+include "other.thrift"
+`,
+		"services/other.thrift":  "struct Other {}\n",
+		"common/user.thrift":     "include \"../services/other.thrift\"\n",
+		"common/internal.thrift": "struct Internal {}\n",
+		"shared/shared.thrift":   "namespace go acme.shared\n",
+		"other/external.thrift":  "namespace go acme.other\n",
+	})
+	require.Equal(t, []string{
+		`service.thrift:3:1:error: include of "../common/internal.thrift" violates layering rule "deny services/** -> common/internal.thrift" (include.layering)`,
+		`service.thrift:5:1:error: include of "../other/external.thrift" is not allowed by layering rule "allow services/** -> common/**", "allow services/** -> namespace:go=acme.shared" (include.layering)`,
+		`service.thrift:6:1:error: include of "other.thrift" is not allowed by layering rule "allow services/** -> common/**", "allow services/** -> namespace:go=acme.shared" (include.layering)`,
+		`user.thrift:1:1:error: include of "../services/other.thrift" violates layering rule "deny common/** -> services/**" (include.layering)`,
+	}, messages)
+}
@@ -6,6 +6,12 @@ import (
 )
 
 // CheckMapKeys verifies that map keys are valid types.
+//
+// Key types are resolved through typedefs, so "map<UserId, X>" is valid where UserId is a typedef
+// of an integer type.
+//
+// Nested maps, eg. "map<string, map<Key, X>>", are checked as the linter walks into the value type,
+// so the check does not recurse itself; doing so would report nested keys twice.
 func CheckMapKeys() thriftlint.Check {
 	return thriftlint.MakeCheck("map", checkMapKeys)
 }
@@ -13,18 +19,21 @@ func CheckMapKeys() thriftlint.Check {
 func checkMapKeys(file *parser.Thrift, t *parser.Type) (messages thriftlint.Messages) {
 	if t.Name == "map" {
 		kn := t.KeyType.Name
-		if kn != "string" && kn != "i16" && kn != "i32" && kn != "i64" && kn != "double" {
-			// Not an integral type, check if it's an enum and allow it if so.
-			resolved := thriftlint.Resolve(kn, file)
-			isEnum := false
-			if resolved != nil {
-				_, isEnum = resolved.(*parser.Enum)
-			}
-			if !isEnum {
+		resolved, err := thriftlint.ResolveType(t.KeyType, file)
+		if err != nil {
+			// Unknown types are reported by the "types" check.
+			return
+		}
+		switch resolved.Kind {
+		case thriftlint.EnumKind:
+		case thriftlint.BuiltinKind:
+			rn := resolved.Type.Name
+			if rn != "string" && rn != "i16" && rn != "i32" && rn != "i64" && rn != "double" {
 				messages.Error(t, "map keys must be string, enum, integer or double, not %q", kn)
 			}
+		default:
+			messages.Error(t, "map keys must be string, enum, integer or double, not %q", kn)
 		}
-		return checkMapKeys(file, t.ValueType)
 	}
 	return
 }
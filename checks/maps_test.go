@@ -0,0 +1,32 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckMapKeys(t *testing.T) {
+	messages := lintSource(t, CheckMapKeys(), `
+enum Color { RED = 0 }
+typedef i64 UserId
+typedef list<string> Names
+struct Key {}
+
+struct Test {
+  1: map<string, i32> strings
+  2: map<Color, i32> colors
+  3: map<UserId, i32> users
+  4: map<Names, i32> names
+  5: map<Key, i32> keys
+  6: map<string, map<Key, i32>> nested
+  7: list<map<bool, i32>> listed
+}
+`)
+	require.Equal(t, []string{
+		`test.thrift:11:6:error: map keys must be string, enum, integer or double, not "Names" (map)`,
+		`test.thrift:12:6:error: map keys must be string, enum, integer or double, not "Key" (map)`,
+		`test.thrift:13:18:error: map keys must be string, enum, integer or double, not "Key" (map)`,
+		`test.thrift:14:11:error: map keys must be string, enum, integer or double, not "bool" (map)`,
+	}, messages)
+}
@@ -0,0 +1,93 @@
+package checks
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/alecthomas/go-thrift/parser"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+var fieldQualifierRegex = regexp.MustCompile(`^-?\d+\s*:\s*(optional|required)\b`)
+
+// CheckMethodOneway checks that oneway methods return void and do not throw exceptions, as the
+// caller never receives a response.
+func CheckMethodOneway() thriftlint.Check {
+	return thriftlint.MakeCheck("method.oneway", func(m *parser.Method) (messages thriftlint.Messages) {
+		if !m.Oneway {
+			return
+		}
+		if m.ReturnType != nil {
+			messages.Error(m.ReturnType, "oneway method %s must return void, not %s", m.Name, m.ReturnType)
+		}
+		if len(m.Exceptions) > 0 {
+			messages.Error(m.Exceptions[0], "oneway method %s must not declare exceptions", m.Name)
+		}
+		return
+	})
+}
+
+// CheckMethodThrows checks that exceptions in throws clauses are not qualified with optional or
+// required, which the parser otherwise silently ignores.
+//
+// That throws clauses only contain exceptions is checked by "types.throws", and that their IDs
+// are unique by "field.id.duplicate".
+func CheckMethodThrows() thriftlint.Check {
+	return thriftlint.MakeCheck("method.throws", func(project *thriftlint.Project, file *parser.Thrift, m *parser.Method) (messages thriftlint.Messages) {
+		source := project.Source(file)
+		for _, e := range m.Exceptions {
+			// The position of a field is that of any comment preceding it, so skip comments too.
+			offset := source.Offset(e.Pos)
+			if offset < 0 {
+				continue
+			}
+			code := bytes.TrimLeft(source.Code()[offset:], " \t\r\n")
+			if match := fieldQualifierRegex.FindSubmatch(code); match != nil {
+				messages.Warning(e, "exception %q of %s should not be %s", e.Name, m.Name, match[1])
+			}
+		}
+		return
+	})
+}
+
+// CheckMethodArguments checks that the argument names of a method are unique.
+func CheckMethodArguments() thriftlint.Check {
+	return thriftlint.MakeCheck("method.arguments", func(m *parser.Method) (messages thriftlint.Messages) {
+		seen := map[string]*parser.Field{}
+		for _, arg := range m.Arguments {
+			if prev, ok := seen[arg.Name]; ok {
+				messages.Error(arg, "argument %q of %s is already declared at %d:%d", arg.Name, m.Name,
+					prev.Pos.Line, prev.Pos.Col)
+				continue
+			}
+			seen[arg.Name] = arg
+		}
+		return
+	})
+}
+
+// CheckMethodInherited checks that methods do not redeclare methods inherited from a base service.
+//
+// Base services are resolved through "extends" across includes.
+func CheckMethodInherited() thriftlint.Check {
+	return thriftlint.MakeCheck("method.inherited", func(file *parser.Thrift, s *parser.Service, m *parser.Method) (messages thriftlint.Messages) {
+		seen := map[*parser.Service]bool{s: true}
+		extends, extendsFile := s.Extends, file
+		for extends != "" {
+			definition, definitionFile, _ := thriftlint.ResolveDefinition(extends, extendsFile)
+			base, ok := definition.(*parser.Service)
+			// Unknown bases are reported by "types.extends".
+			if !ok || seen[base] {
+				return
+			}
+			seen[base] = true
+			if _, ok := base.Methods[m.Name]; ok {
+				messages.Error(m, "%s.%s redeclares method inherited from %s", s.Name, m.Name, extends)
+				return
+			}
+			extends, extendsFile = base.Extends, definitionFile
+		}
+		return
+	})
+}
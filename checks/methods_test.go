@@ -0,0 +1,39 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+func TestCheckMethods(t *testing.T) {
+	checks := thriftlint.Checks{CheckMethodOneway(), CheckMethodThrows(), CheckMethodArguments(), CheckMethodInherited()}
+	messages := lintTest(t, checks, "test.thrift", map[string]string{"test.thrift": `
+exception Error {}
+
+service Base {
+  void ping()
+}
+
+service Service extends Base {
+  oneway i32 notify() throws (1: Error e)
+  void get(1: string a, 2: string a) throws (
+    1: optional Error a,
+    // Comments before the field are part of its position.
+    2: required Error b,
+    /* 3: optional */ 3: Error c
+  )
+  void ping()
+}
+`})
+	require.Equal(t, []string{
+		`test.thrift:10:25:error: argument "a" of get is already declared at 10:12 (method.arguments)`,
+		`test.thrift:11:5:warning: exception "a" of get should not be optional (method.throws)`,
+		`test.thrift:12:5:warning: exception "b" of get should not be required (method.throws)`,
+		`test.thrift:16:0:error: Service.ping redeclares method inherited from Base (method.inherited)`,
+		`test.thrift:9:10:error: oneway method notify must return void, not i32 (method.oneway)`,
+		`test.thrift:9:31:error: oneway method notify must not declare exceptions (method.oneway)`,
+	}, messages)
+}
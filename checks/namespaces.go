@@ -0,0 +1,223 @@
+package checks
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/go-thrift/parser"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+// NamespacePathMapping maps namespaces to the directories files declaring them must be in.
+//
+// A file declaring a namespace for Scope that starts with Prefix must be in a directory whose
+// path ends with Dir, followed by the remainder of the namespace with "." replaced by "/". eg.
+// with {Scope: "java", Prefix: "com.acme", Dir: "idl"}, a file declaring "namespace java
+// com.acme.payments.v1" must be in ".../idl/payments/v1/".
+type NamespacePathMapping struct {
+	Scope  string
+	Prefix string
+	Dir    string
+}
+
+// ParseNamespacePathMapping parses a mapping of the form "SCOPE:PREFIX=DIR".
+func ParseNamespacePathMapping(mapping string) (*NamespacePathMapping, error) {
+	colon := strings.Index(mapping, ":")
+	equals := strings.Index(mapping, "=")
+	if colon <= 0 || equals < colon {
+		return nil, fmt.Errorf("invalid namespace path mapping %q, expected SCOPE:PREFIX=DIR", mapping)
+	}
+	return &NamespacePathMapping{
+		Scope:  mapping[:colon],
+		Prefix: mapping[colon+1 : equals],
+		Dir:    strings.Trim(mapping[equals+1:], "/"),
+	}, nil
+}
+
+// Find the namespace declared for scope, if any.
+func findNamespace(project *thriftlint.Project, file *parser.Thrift, scope string) *thriftlint.Namespace {
+	for _, namespace := range thriftlint.Namespaces(file, project.Source(file)) {
+		if namespace.Scope == scope {
+			return namespace
+		}
+	}
+	return nil
+}
+
+// CheckNamespacesRequired checks that every file declares a namespace for each of scopes, eg.
+// "go" and "java".
+func CheckNamespacesRequired(scopes []string) thriftlint.Check {
+	return thriftlint.MakeCheck("namespace.required", func(file *parser.Thrift) (messages thriftlint.Messages) {
+		for _, scope := range scopes {
+			if _, ok := file.Namespaces[scope]; !ok {
+				messages.Error(&thriftlint.Namespace{Pos: parser.Pos{Line: 1, Col: 1}, Scope: scope},
+					"missing %s namespace", scope)
+			}
+		}
+		return
+	})
+}
+
+// NamespacePattern is a regular expression that namespaces for Scope must match in full.
+type NamespacePattern struct {
+	Scope   string
+	Pattern string
+
+	regex *regexp.Regexp
+}
+
+// ParseNamespacePattern parses a pattern of the form "SCOPE=REGEX".
+func ParseNamespacePattern(pattern string) (*NamespacePattern, error) {
+	parts := strings.SplitN(pattern, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return nil, fmt.Errorf("invalid namespace pattern %q, expected SCOPE=REGEX", pattern)
+	}
+	regex, err := regexp.Compile("^(?:" + parts[1] + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace pattern %q: %s", pattern, err)
+	}
+	return &NamespacePattern{Scope: parts[0], Pattern: parts[1], regex: regex}, nil
+}
+
+// CheckNamespacesPattern checks namespaces against the patterns for their scope.
+func CheckNamespacesPattern(patterns []*NamespacePattern) thriftlint.Check {
+	return thriftlint.MakeCheck("namespace.pattern", func(project *thriftlint.Project, file *parser.Thrift) (messages thriftlint.Messages) {
+		for _, namespace := range thriftlint.Namespaces(file, project.Source(file)) {
+			for _, pattern := range patterns {
+				if pattern.Scope == namespace.Scope && !pattern.regex.MatchString(namespace.Value) {
+					messages.Error(namespace, "%s namespace %q should match %q", namespace.Scope, namespace.Value,
+						pattern.Pattern)
+				}
+			}
+		}
+		return
+	})
+}
+
+// CheckNamespacesConsistent checks that the namespaces declared for each group of scopes end in
+// the same package name. eg. with the group {"go", "java"}, "namespace java com.acme.payments"
+// requires a Go namespace ending in "payments".
+func CheckNamespacesConsistent(groups [][]string) thriftlint.Check {
+	return thriftlint.MakeCheck("namespace.consistent", func(project *thriftlint.Project, file *parser.Thrift) (messages thriftlint.Messages) {
+		for _, group := range groups {
+			var first *thriftlint.Namespace
+			for _, scope := range group {
+				namespace := findNamespace(project, file, scope)
+				if namespace == nil {
+					continue
+				}
+				if first == nil {
+					first = namespace
+					continue
+				}
+				if packageName(namespace.Value) != packageName(first.Value) {
+					messages.Error(namespace, "%s namespace %q should end in %q to match %s namespace %q",
+						namespace.Scope, namespace.Value, packageName(first.Value), first.Scope, first.Value)
+				}
+			}
+		}
+		return
+	})
+}
+
+// The last component of a namespace.
+func packageName(namespace string) string {
+	return namespace[strings.LastIndexAny(namespace, "./")+1:]
+}
+
+// CheckNamespacesPath checks that files are in the directory corresponding to their namespace, per
+// mappings.
+func CheckNamespacesPath(mappings []*NamespacePathMapping) thriftlint.Check {
+	return thriftlint.MakeCheck("namespace.path", func(project *thriftlint.Project, file *parser.Thrift) (messages thriftlint.Messages) {
+		dir := filepath.ToSlash(filepath.Dir(file.Filename))
+		for _, mapping := range mappings {
+			namespace := findNamespace(project, file, mapping.Scope)
+			if namespace == nil || (namespace.Value != mapping.Prefix && !strings.HasPrefix(namespace.Value, mapping.Prefix+".")) {
+				continue
+			}
+			rest := strings.TrimPrefix(strings.TrimPrefix(namespace.Value, mapping.Prefix), ".")
+			expected := strings.Trim(mapping.Dir+"/"+strings.Replace(rest, ".", "/", -1), "/")
+			if dir != expected && !strings.HasSuffix(dir, "/"+expected) {
+				messages.Error(namespace, "file with %s namespace %q should be in directory %q", mapping.Scope,
+					namespace.Value, expected)
+			}
+		}
+		return
+	})
+}
+
+type namespaceConflictsKey struct{}
+
+// A definition with the same name as one in another file declaring the same namespace.
+type namespaceConflict struct {
+	definition *thriftlint.Definition
+	other      *parser.Thrift
+	scope      string
+	namespace  string
+}
+
+// CheckNamespacesConflict checks for definitions with the same name in different files that
+// declare the same namespace, which would conflict in generated code.
+func CheckNamespacesConflict() thriftlint.Check {
+	return thriftlint.MakeCheck("namespace.conflict", func(project *thriftlint.Project, file *parser.Thrift) (messages thriftlint.Messages) {
+		conflicts := project.Value(namespaceConflictsKey{}, func() interface{} {
+			return namespaceConflicts(project)
+		}).(map[*parser.Thrift][]*namespaceConflict)
+		for _, conflict := range conflicts[file] {
+			definition := conflict.definition
+			messages.Error(definition.Node, "%s %s is also defined in %s, which declares the same %s namespace %q",
+				definition.Kind, definition.Name, filepath.Base(conflict.other.Filename), conflict.scope, conflict.namespace)
+		}
+		return
+	})
+}
+
+// Find the namespace conflicts of each file in the project. Each definition is reported once, for
+// the first scope and conflicting file in sorted order.
+func namespaceConflicts(project *thriftlint.Project) map[*parser.Thrift][]*namespaceConflict {
+	// Map of "scope namespace" to the files declaring it.
+	namespaceFiles := map[string][]*parser.Thrift{}
+	for _, file := range project.Files {
+		for scope, namespace := range file.Namespaces {
+			key := scope + " " + namespace
+			namespaceFiles[key] = append(namespaceFiles[key], file)
+		}
+	}
+	for _, files := range namespaceFiles {
+		sort.Slice(files, func(i, j int) bool { return files[i].Filename < files[j].Filename })
+	}
+	definitions := map[*parser.Thrift][]*thriftlint.Definition{}
+	for _, definition := range project.Index().Definitions {
+		definitions[definition.File] = append(definitions[definition.File], definition)
+	}
+
+	out := map[*parser.Thrift][]*namespaceConflict{}
+	for _, file := range project.Files {
+		reported := map[*thriftlint.Definition]bool{}
+		scopes := []string{}
+		for scope := range file.Namespaces {
+			scopes = append(scopes, scope)
+		}
+		sort.Strings(scopes)
+		for _, scope := range scopes {
+			namespace := file.Namespaces[scope]
+			for _, other := range namespaceFiles[scope+" "+namespace] {
+				if other == file {
+					continue
+				}
+				for _, definition := range definitions[file] {
+					if reported[definition] || thriftlint.Resolve(definition.Name, other) == nil {
+						continue
+					}
+					reported[definition] = true
+					out[file] = append(out[file], &namespaceConflict{definition, other, scope, namespace})
+				}
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,96 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+func TestParseNamespacePattern(t *testing.T) {
+	pattern, err := ParseNamespacePattern("go=acme\\.[a-z]+")
+	require.NoError(t, err)
+	require.Equal(t, "go", pattern.Scope)
+	require.Equal(t, "acme\\.[a-z]+", pattern.Pattern)
+	for _, invalid := range []string{"go", "=acme", "go=acme.("} {
+		_, err := ParseNamespacePattern(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestParseNamespacePathMapping(t *testing.T) {
+	mapping, err := ParseNamespacePathMapping("java:com.acme=/idl/")
+	require.NoError(t, err)
+	require.Equal(t, &NamespacePathMapping{Scope: "java", Prefix: "com.acme", Dir: "idl"}, mapping)
+	for _, invalid := range []string{"java", ":com.acme=idl", "java=idl:com.acme"} {
+		_, err := ParseNamespacePathMapping(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestCheckNamespaces(t *testing.T) {
+	pattern, err := ParseNamespacePattern("go=acme\\.[a-z]+")
+	require.NoError(t, err)
+	mapping, err := ParseNamespacePathMapping("java:com.acme=idl")
+	require.NoError(t, err)
+	checks := thriftlint.Checks{
+		CheckNamespacesRequired([]string{"go", "java"}),
+		CheckNamespacesPattern([]*NamespacePattern{pattern}),
+		CheckNamespacesConsistent([][]string{{"go", "java"}}),
+		CheckNamespacesPath([]*NamespacePathMapping{mapping}),
+	}
+	messages := lintTest(t, checks, "idl/payments/main.thrift", map[string]string{
+		"idl/payments/main.thrift": `
+include "../billing.thrift"
+include "../other.thrift"
+
+namespace go acme.payments
+namespace java com.acme.payments
+`,
+		"idl/billing.thrift": `
+// namespace go Acme.Billing
+namespace go acme.billing.v1
+namespace java com.acme.billing
+`,
+		"idl/other.thrift": `
+namespace go acme.users
+`,
+	})
+	require.Equal(t, []string{
+		`billing.thrift:3:1:error: go namespace "acme.billing.v1" should match "acme\\.[a-z]+" (namespace.pattern)`,
+		`billing.thrift:4:1:error: file with java namespace "com.acme.billing" should be in directory "idl/billing" (namespace.path)`,
+		`billing.thrift:4:1:error: java namespace "com.acme.billing" should end in "v1" to match go namespace "acme.billing.v1" (namespace.consistent)`,
+		`other.thrift:1:1:error: missing java namespace (namespace.required)`,
+	}, messages)
+}
+
+func TestCheckNamespacesConflict(t *testing.T) {
+	messages := lintTest(t, thriftlint.Checks{CheckNamespacesConflict()}, "main.thrift", map[string]string{
+		"main.thrift": `
+include "a.thrift"
+include "b.thrift"
+
+namespace go acme
+namespace java com.acme
+
+struct User {}
+struct Account {}
+`,
+		"a.thrift": `
+namespace go acme
+namespace java com.acme
+
+struct User {}
+`,
+		"b.thrift": `
+namespace go other
+
+struct Account {}
+`,
+	})
+	require.Equal(t, []string{
+		`a.thrift:5:8:error: struct User is also defined in main.thrift, which declares the same go namespace "acme" (namespace.conflict)`,
+		`main.thrift:8:8:error: struct User is also defined in a.thrift, which declares the same go namespace "acme" (namespace.conflict)`,
+	}, messages)
+}
@@ -8,9 +8,15 @@ import (
 
 // CheckOptional ensures that all Thrift fields are optional, as is generally accepted best
 // practice for Thrift.
+//
+// Container fields, including typedefs of containers, are exempt.
 func CheckOptional() thriftlint.Check {
-	return thriftlint.MakeCheck("optional", func(s *parser.Struct, f *parser.Field) (messages thriftlint.Messages) {
-		if f.Type.Name != "list" && f.Type.Name != "set" && f.Type.Name != "map" && !f.Optional {
+	return thriftlint.MakeCheck("optional", func(file *parser.Thrift, s *parser.Struct, f *parser.Field) (messages thriftlint.Messages) {
+		name := f.Type.Name
+		if resolved, err := thriftlint.ResolveType(f.Type, file); err == nil {
+			name = resolved.Type.Name
+		}
+		if name != "list" && name != "set" && name != "map" && !f.Optional {
 			messages.Warning(f, "%s must be optional", f.Name)
 		}
 		return
@@ -0,0 +1,145 @@
+package checks
+
+import (
+	"strings"
+
+	"github.com/alecthomas/go-thrift/parser"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+// A field of a struct that contains another struct.
+type containment struct {
+	field *parser.Field
+	to    *parser.Struct
+	// Required is true if the contained struct must be present to construct the container, ie. the
+	// field is not optional, not in a union and not an element of a container type.
+	required bool
+}
+
+type containmentKey struct{}
+
+// Build the graph of struct containment across all files in project, following typedefs.
+func containmentGraph(project *thriftlint.Project) map[*parser.Struct][]*containment {
+	return project.Value(containmentKey{}, func() interface{} {
+		graph := map[*parser.Struct][]*containment{}
+		for _, file := range project.Files {
+			for _, s := range file.Structs {
+				graph[s] = structContainments(file, s, true)
+			}
+			for _, s := range file.Exceptions {
+				graph[s] = structContainments(file, s, true)
+			}
+			for _, s := range file.Unions {
+				graph[s] = structContainments(file, s, false)
+			}
+		}
+		return graph
+	}).(map[*parser.Struct][]*containment)
+}
+
+func structContainments(file *parser.Thrift, s *parser.Struct, canRequire bool) []*containment {
+	out := []*containment{}
+	for _, field := range s.Fields {
+		field := field
+		var contain func(t *parser.Type, file *parser.Thrift, required bool)
+		contain = func(t *parser.Type, file *parser.Thrift, required bool) {
+			if t == nil {
+				return
+			}
+			resolved, err := thriftlint.ResolveType(t, file)
+			if err != nil {
+				// Reported by the types check.
+				return
+			}
+			switch resolved.Kind {
+			case thriftlint.ContainerKind:
+				contain(resolved.Type.KeyType, resolved.File, false)
+				contain(resolved.Type.ValueType, resolved.File, false)
+			case thriftlint.StructKind, thriftlint.UnionKind, thriftlint.ExceptionKind:
+				out = append(out, &containment{field: field, to: resolved.Definition.(*parser.Struct), required: required})
+			}
+		}
+		contain(field.Type, file, canRequire && !field.Optional)
+	}
+	return out
+}
+
+// Find the shortest chain of fields from "from" back to "to", optionally through required fields
+// only.
+func containmentPath(graph map[*parser.Struct][]*containment, from, to *parser.Struct, requiredOnly bool) []*containment {
+	// The edge by which each struct was reached, and the struct it was reached from.
+	previous := map[*parser.Struct]*containment{}
+	parents := map[*parser.Struct]*parser.Struct{}
+	queue := []*parser.Struct{from}
+	seen := map[*parser.Struct]bool{from: true}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		for _, edge := range graph[s] {
+			if requiredOnly && !edge.required {
+				continue
+			}
+			if edge.to == to {
+				path := []*containment{edge}
+				for n := s; n != from; n = parents[n] {
+					path = append([]*containment{previous[n]}, path...)
+				}
+				return path
+			}
+			if !seen[edge.to] {
+				seen[edge.to] = true
+				previous[edge.to] = edge
+				parents[edge.to] = s
+				queue = append(queue, edge.to)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckRecursion checks for structs that contain themselves, directly or through other structs.
+//
+// A struct that contains itself through required, non-container fields can not be constructed,
+// and is an error. Other recursion, eg. through optional fields, is legal but is reported as a
+// warning as some generators do not support recursive types.
+func CheckRecursion() thriftlint.Check {
+	return thriftlint.MakeCheck("recursion", func(project *thriftlint.Project, s *parser.Struct) (messages thriftlint.Messages) {
+		graph := containmentGraph(project)
+		reported := map[*parser.Field]bool{}
+		for _, edge := range graph[s] {
+			if reported[edge.field] {
+				continue
+			}
+			// Find the rest of the cycle back to s, if the field does not contain s directly.
+			recursive := func(requiredOnly bool) ([]*containment, bool) {
+				if edge.to == s {
+					return nil, true
+				}
+				path := containmentPath(graph, edge.to, s, requiredOnly)
+				return path, path != nil
+			}
+			if path, ok := recursive(true); ok && edge.required {
+				reported[edge.field] = true
+				messages.Error(edge.field, "%s can not be constructed, it contains itself through %s", s.Name,
+					containmentChain(s, edge, path))
+			} else if path, ok := recursive(false); ok {
+				reported[edge.field] = true
+				messages.Warning(edge.field, "%s is recursive through %s, which some generators do not support", s.Name,
+					containmentChain(s, edge, path))
+			}
+		}
+		return
+	})
+}
+
+// Format a cycle starting at s, eg. "A.b -> B.a -> A".
+func containmentChain(s *parser.Struct, first *containment, path []*containment) string {
+	links := []string{s.Name + "." + first.field.Name}
+	from := first.to
+	for _, edge := range path {
+		links = append(links, from.Name+"."+edge.field.Name)
+		from = edge.to
+	}
+	return strings.Join(append(links, s.Name), " -> ")
+}
@@ -0,0 +1,74 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/alecthomas/go-thrift/parser"
+	"github.com/stretchr/testify/require"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+func TestCheckRecursion(t *testing.T) {
+	messages := lintTest(t, thriftlint.Checks{CheckRecursion()}, "test.thrift", map[string]string{
+		"test.thrift": `
+include "common.thrift"
+
+struct Self {
+  1: required Self self
+}
+
+struct A {
+  1: required common.B b
+}
+
+typedef Node NodeAlias
+
+struct Node {
+  1: NodeAlias next
+}
+
+struct Optional {
+  1: optional Optional next
+}
+
+union Tree {
+  1: string leaf
+  2: Tree branch
+}
+
+struct List {
+  1: required list<List> children
+}
+`,
+		"common.thrift": `
+include "test.thrift"
+
+struct B {
+  1: required test.A a
+}
+`,
+	})
+	require.Equal(t, []string{
+		`common.thrift:5:3:error: B can not be constructed, it contains itself through B.a -> A.b -> B (recursion)`,
+		`test.thrift:15:3:error: Node can not be constructed, it contains itself through Node.next -> Node (recursion)`,
+		`test.thrift:19:3:warning: Optional is recursive through Optional.next -> Optional, which some generators do not support (recursion)`,
+		`test.thrift:24:3:warning: Tree is recursive through Tree.branch -> Tree, which some generators do not support (recursion)`,
+		`test.thrift:28:3:warning: List is recursive through List.children -> List, which some generators do not support (recursion)`,
+		`test.thrift:5:3:error: Self can not be constructed, it contains itself through Self.self -> Self (recursion)`,
+		`test.thrift:9:3:error: A can not be constructed, it contains itself through A.b -> B.a -> A (recursion)`,
+	}, messages)
+}
+
+func TestContainmentChain(t *testing.T) {
+	a := &parser.Struct{Name: "A"}
+	b := &parser.Struct{Name: "B"}
+	c := &parser.Struct{Name: "C"}
+	first := &containment{field: &parser.Field{Name: "b"}, to: b}
+	path := []*containment{
+		{field: &parser.Field{Name: "c"}, to: c},
+		{field: &parser.Field{Name: "a"}, to: a},
+	}
+	require.Equal(t, "A.b -> B.c -> C.a -> A", containmentChain(a, first, path))
+	require.Equal(t, "A.a -> A", containmentChain(a, &containment{field: &parser.Field{Name: "a"}, to: a}, nil))
+}
@@ -0,0 +1,47 @@
+package checks
+
+import (
+	"github.com/alecthomas/go-thrift/parser"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+// CheckConstantReferences checks that identifiers in constant values and field defaults refer to
+// constants or enum values, eg. "LIMIT", "pkg.LIMIT", "Enum.VALUE" or "pkg.Enum.VALUE".
+func CheckConstantReferences() thriftlint.Check {
+	return thriftlint.MakeCheck("references", func(file *parser.Thrift, self interface{}) (messages thriftlint.Messages) {
+		switch node := self.(type) {
+		case *parser.Constant:
+			checkValueReferences(file, node, node.Value, &messages)
+		case *parser.Field:
+			checkValueReferences(file, node, node.Default, &messages)
+		}
+		return
+	})
+}
+
+func checkValueReferences(file *parser.Thrift, node interface{}, value interface{}, messages *thriftlint.Messages) {
+	switch value := value.(type) {
+	case parser.Identifier:
+		symbol := string(value)
+		// go-thrift parses boolean literals as identifiers.
+		if symbol == "true" || symbol == "false" {
+			return
+		}
+		definition, _, kind := thriftlint.ResolveDefinition(symbol, file)
+		if definition == nil {
+			messages.Error(node, "unknown constant %q", symbol)
+		} else if kind != thriftlint.ConstantKind && kind != thriftlint.EnumValueKind {
+			messages.Error(node, "%q is %s, not a constant or enum value", symbol, article(kind))
+		}
+	case []interface{}:
+		for _, v := range value {
+			checkValueReferences(file, node, v, messages)
+		}
+	case []parser.KeyValue:
+		for _, kv := range value {
+			checkValueReferences(file, node, kv.Key, messages)
+			checkValueReferences(file, node, kv.Value, messages)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package checks
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/go-thrift/parser"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+// Annotations used to reserve IDs and names.
+const (
+	ReservedIDsAnnotation   = "thriftlint.reserved_ids"
+	ReservedNamesAnnotation = "thriftlint.reserved_names"
+)
+
+// CheckReserved checks that fields, enum values and methods do not use IDs or names reserved by
+// annotations on their enclosing struct, union, exception, enum or service. eg.
+//
+//	struct User {
+//	  1: optional string name
+//	} (thriftlint.reserved_ids = "2,4-6", thriftlint.reserved_names = "email")
+//
+// Reserving the IDs and names of deleted fields prevents them from being accidentally reused.
+func CheckReserved() thriftlint.Check {
+	return thriftlint.MakeCheck("reserved", func(self interface{}) (messages thriftlint.Messages) {
+		switch node := self.(type) {
+		case *parser.Struct:
+			ids, names := reserved(node)
+			for _, f := range node.Fields {
+				if ids.contains(f.ID) {
+					messages.Error(f, "field %q uses reserved ID %d", f.Name, f.ID)
+				}
+				if names[f.Name] {
+					messages.Error(f, "field name %q is reserved", f.Name)
+				}
+			}
+
+		case *parser.Enum:
+			ids, names := reserved(node)
+			for _, v := range sortedEnumValues(node) {
+				if ids.contains(v.Value) {
+					messages.Error(v, "enum value %q uses reserved value %d", v.Name, v.Value)
+				}
+				if names[v.Name] {
+					messages.Error(v, "enum value name %q is reserved", v.Name)
+				}
+			}
+
+		case *parser.Service:
+			_, names := reserved(node)
+			methods := []*parser.Method{}
+			for _, m := range node.Methods {
+				methods = append(methods, m)
+			}
+			sort.Slice(methods, func(i, j int) bool {
+				if methods[i].Pos.Line != methods[j].Pos.Line {
+					return methods[i].Pos.Line < methods[j].Pos.Line
+				}
+				return methods[i].Pos.Col < methods[j].Pos.Col
+			})
+			for _, m := range methods {
+				if names[m.Name] {
+					messages.Error(m, "method name %q is reserved", m.Name)
+				}
+			}
+		}
+		return
+	})
+}
+
+// Inclusive ranges of reserved IDs.
+type idRanges [][2]int
+
+func (r idRanges) contains(id int) bool {
+	for _, rng := range r {
+		if id >= rng[0] && id <= rng[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// Extract reserved IDs and names from a node's annotations. Malformed values are ignored, as they
+// are reported by the "annotations" check.
+func reserved(node interface{}) (idRanges, map[string]bool) {
+	ids := idRanges{}
+	if annotation := thriftlint.Annotation(node, ReservedIDsAnnotation, ""); annotation != "" {
+		for _, part := range strings.Split(annotation, ",") {
+			if rng, ok := parseIDRange(strings.TrimSpace(part)); ok {
+				ids = append(ids, rng)
+			}
+		}
+	}
+	names := map[string]bool{}
+	if annotation := thriftlint.Annotation(node, ReservedNamesAnnotation, ""); annotation != "" {
+		for _, name := range strings.Split(annotation, ",") {
+			names[strings.TrimSpace(name)] = true
+		}
+	}
+	return ids, names
+}
+
+// Parse "N" or "N-M", where either may be negative.
+func parseIDRange(s string) ([2]int, bool) {
+	if s == "" {
+		return [2]int{}, false
+	}
+	// Find a range separator that is not a leading minus sign.
+	if sep := strings.Index(s[1:], "-"); sep >= 0 {
+		sep++
+		start, err := strconv.Atoi(strings.TrimSpace(s[:sep]))
+		if err != nil {
+			return [2]int{}, false
+		}
+		end, err := strconv.Atoi(strings.TrimSpace(s[sep+1:]))
+		if err != nil {
+			return [2]int{}, false
+		}
+		return [2]int{start, end}, true
+	}
+	id, err := strconv.Atoi(s)
+	if err != nil {
+		return [2]int{}, false
+	}
+	return [2]int{id, id}, true
+}
@@ -0,0 +1,112 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+func TestCheckReserved(t *testing.T) {
+	messages := lintSource(t, CheckReserved(), `
+struct User {
+  1: optional string name
+  2: optional string email
+  5: optional string phone
+  7: optional string address
+} (thriftlint.reserved_ids = "2, 4-6, bad", thriftlint.reserved_names = "email, address")
+
+enum Color {
+  RED = 0
+  GREEN = 1
+  BLUE = 3
+} (thriftlint.reserved_ids = "1-2,3", thriftlint.reserved_names = "RED")
+
+service Users {
+  void getUser()
+  void deleteUser()
+} (thriftlint.reserved_names = "deleteUser")
+`)
+	require.Equal(t, []string{
+		`test.thrift:10:3:error: enum value name "RED" is reserved (reserved)`,
+		`test.thrift:11:0:error: enum value "GREEN" uses reserved value 1 (reserved)`,
+		`test.thrift:12:0:error: enum value "BLUE" uses reserved value 3 (reserved)`,
+		`test.thrift:17:3:error: method name "deleteUser" is reserved (reserved)`,
+		`test.thrift:4:3:error: field "email" uses reserved ID 2 (reserved)`,
+		`test.thrift:4:3:error: field name "email" is reserved (reserved)`,
+		`test.thrift:5:3:error: field "phone" uses reserved ID 5 (reserved)`,
+		`test.thrift:6:3:error: field name "address" is reserved (reserved)`,
+	}, messages)
+}
+
+func TestCheckReservedMethodOrder(t *testing.T) {
+	source := `
+service Users {
+  void zeta() void beta()
+  void alpha()
+} (thriftlint.reserved_names = "alpha,beta,zeta")
+`
+	// Methods are reported in source order, not map order.
+	for i := 0; i < 10; i++ {
+		messages := lintMessages(t, thriftlint.Checks{CheckReserved()}, "test.thrift",
+			map[string]string{"test.thrift": source})
+		lines := []string{}
+		for _, msg := range messages {
+			lines = append(lines, formatTestMessage(msg))
+		}
+		require.Equal(t, []string{
+			`test.thrift:3:0:error: method name "zeta" is reserved (reserved)`,
+			`test.thrift:3:15:error: method name "beta" is reserved (reserved)`,
+			`test.thrift:4:3:error: method name "alpha" is reserved (reserved)`,
+		}, lines)
+	}
+}
+
+func TestCheckReservedEnumOrder(t *testing.T) {
+	source := `
+enum Color {
+  RED = 1
+  GREEN = 1
+  BLUE = 1
+} (thriftlint.reserved_ids = "1")
+`
+	// Values that share a number are reported in source order, not map order.
+	for i := 0; i < 10; i++ {
+		messages := lintMessages(t, thriftlint.Checks{CheckReserved()}, "test.thrift",
+			map[string]string{"test.thrift": source})
+		lines := []string{}
+		for _, msg := range messages {
+			lines = append(lines, formatTestMessage(msg))
+		}
+		require.Equal(t, []string{
+			`test.thrift:3:3:error: enum value "RED" uses reserved value 1 (reserved)`,
+			`test.thrift:4:0:error: enum value "GREEN" uses reserved value 1 (reserved)`,
+			`test.thrift:5:0:error: enum value "BLUE" uses reserved value 1 (reserved)`,
+		}, lines)
+	}
+}
+
+func TestParseIDRange(t *testing.T) {
+	tests := []struct {
+		input string
+		rng   [2]int
+		ok    bool
+	}{
+		{"1", [2]int{1, 1}, true},
+		{"-3", [2]int{-3, -3}, true},
+		{"4-6", [2]int{4, 6}, true},
+		{"4 - 6", [2]int{4, 6}, true},
+		{"-5--2", [2]int{-5, -2}, true},
+		{"-5-2", [2]int{-5, 2}, true},
+		{"", [2]int{}, false},
+		{"a", [2]int{}, false},
+		{"1-", [2]int{}, false},
+		{"x-2", [2]int{}, false},
+	}
+	for _, test := range tests {
+		rng, ok := parseIDRange(test.input)
+		require.Equal(t, test.ok, ok, test.input)
+		require.Equal(t, test.rng, rng, test.input)
+	}
+}
@@ -1,19 +1,81 @@
 package checks
 
 import (
+	"strings"
+
 	"github.com/alecthomas/go-thrift/parser"
 
 	"github.com/UrbanCompass/thriftlint"
 )
 
 // CheckTypeReferences checks that types referenced in Thrift files are actually imported
-// and exist.
+// and exist, and that they refer to types rather than, eg. services or constants.
+//
+// References to typedefs are followed to their underlying type, reporting broken typedef chains
+// and typedef cycles.
 func CheckTypeReferences() thriftlint.Check {
 	return thriftlint.MakeCheck("types", func(file *parser.Thrift, t *parser.Type) (messages thriftlint.Messages) {
-		if !thriftlint.BuiltinThriftTypes[t.Name] && !thriftlint.BuiltinThriftCollections[t.Name] &&
-			thriftlint.Resolve(t.Name, file) == nil {
+		if thriftlint.BuiltinThriftTypes[t.Name] || thriftlint.BuiltinThriftCollections[t.Name] {
+			return
+		}
+		resolved := thriftlint.Resolve(t.Name, file)
+		if resolved == nil {
 			messages.Error(t, "unknown type %q", t.Name)
+			return
+		}
+		rt, err := thriftlint.ResolveType(t, file)
+		if err != nil {
+			messages.Error(t, "invalid type %q: %s", t.Name, err)
+			return
+		}
+		switch rt.Kind {
+		case thriftlint.ServiceKind, thriftlint.ConstantKind:
+			messages.Error(t, "%q is %s, not a type", t.Name, article(rt.Kind))
+		case thriftlint.EnumValueKind:
+			messages.Error(t, "%q is an enum value, not a type", t.Name)
+		}
+		return
+	})
+}
+
+// CheckThrowsTypes checks that the types in method throws clauses are exceptions.
+func CheckThrowsTypes() thriftlint.Check {
+	return thriftlint.MakeCheck("types.throws", func(file *parser.Thrift, m *parser.Method) (messages thriftlint.Messages) {
+		for _, e := range m.Exceptions {
+			rt, err := thriftlint.ResolveType(e.Type, file)
+			if err != nil {
+				// Reported by the "types" check.
+				continue
+			}
+			if rt.Kind != thriftlint.ExceptionKind {
+				messages.Error(e, "%s throws %q which is %s, not an exception", m.Name, e.Type.Name, article(rt.Kind))
+			}
 		}
 		return
 	})
 }
+
+// CheckExtendsTypes checks that services only extend other services.
+func CheckExtendsTypes() thriftlint.Check {
+	return thriftlint.MakeCheck("types.extends", func(file *parser.Thrift, s *parser.Service) (messages thriftlint.Messages) {
+		if s.Extends == "" {
+			return
+		}
+		definition, _, kind := thriftlint.ResolveDefinition(s.Extends, file)
+		if definition == nil {
+			messages.Error(s, "%s extends unknown service %q", s.Name, s.Extends)
+		} else if kind != thriftlint.ServiceKind {
+			messages.Error(s, "%s extends %q which is %s, not a service", s.Name, s.Extends, article(kind))
+		}
+		return
+	})
+}
+
+// Prefix a kind with its indefinite article, eg. "an enum".
+func article(kind thriftlint.Kind) string {
+	name := kind.String()
+	if strings.IndexAny(name[:1], "aeiou") == 0 {
+		return "an " + name
+	}
+	return "a " + name
+}
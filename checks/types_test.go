@@ -0,0 +1,96 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+func TestCheckTypeReferences(t *testing.T) {
+	messages := lintTest(t, thriftlint.Checks{CheckTypeReferences()}, "test.thrift", map[string]string{
+		"common.thrift": `
+struct User {}
+service Base {}
+`,
+		"test.thrift": `
+include "common.thrift"
+
+const i32 LIMIT = 10
+enum Color { RED = 0 }
+typedef Missing Broken
+
+struct Test {
+  1: common.User user
+  2: common.Missing missing
+  3: Unknown unknown
+  4: common.Base base
+  5: LIMIT limit
+  6: Broken broken
+  7: list<Unknown> unknowns
+}
+`,
+	})
+	require.Equal(t, []string{
+		`test.thrift:10:6:error: unknown type "common.Missing" (types)`,
+		`test.thrift:11:6:error: unknown type "Unknown" (types)`,
+		`test.thrift:12:6:error: "common.Base" is a service, not a type (types)`,
+		`test.thrift:13:6:error: "LIMIT" is a constant, not a type (types)`,
+		`test.thrift:14:6:error: invalid type "Broken": unknown type "Missing" (via typedef Broken) (types)`,
+		`test.thrift:15:11:error: unknown type "Unknown" (types)`,
+		`test.thrift:6:9:error: unknown type "Missing" (types)`,
+	}, messages)
+}
+
+func TestCheckThrowsTypes(t *testing.T) {
+	messages := lintSource(t, CheckThrowsTypes(), `
+exception NotFound {}
+struct Problem {}
+enum Code { OK = 0 }
+typedef NotFound Missing
+
+service Test {
+  void ok() throws (1: NotFound notFound, 2: Missing missing)
+  void structs() throws (1: Problem problem)
+  void enums() throws (1: Code code)
+  void unknown() throws (1: Unknown unknown)
+}
+`)
+	require.Equal(t, []string{
+		`test.thrift:10:24:error: enums throws "Code" which is an enum, not an exception (types.throws)`,
+		`test.thrift:9:26:error: structs throws "Problem" which is a struct, not an exception (types.throws)`,
+	}, messages)
+}
+
+func TestCheckExtendsTypes(t *testing.T) {
+	messages := lintTest(t, thriftlint.Checks{CheckExtendsTypes()}, "test.thrift", map[string]string{
+		"common.thrift": `
+service Base {}
+`,
+		"test.thrift": `
+include "common.thrift"
+
+struct Request {}
+exception Failure {}
+
+service Ok extends common.Base {}
+service Struct extends Request {}
+service Exception extends Failure {}
+service Unknown extends Missing {}
+service Plain {}
+`,
+	})
+	require.Equal(t, []string{
+		`test.thrift:10:1:error: Unknown extends unknown service "Missing" (types.extends)`,
+		`test.thrift:8:1:error: Struct extends "Request" which is a struct, not a service (types.extends)`,
+		`test.thrift:9:1:error: Exception extends "Failure" which is an exception, not a service (types.extends)`,
+	}, messages)
+}
+
+func TestArticle(t *testing.T) {
+	require.Equal(t, "an enum", article(thriftlint.EnumKind))
+	require.Equal(t, "an exception", article(thriftlint.ExceptionKind))
+	require.Equal(t, "a struct", article(thriftlint.StructKind))
+	require.Equal(t, "a service", article(thriftlint.ServiceKind))
+}
@@ -0,0 +1,87 @@
+package checks
+
+import (
+	"path/filepath"
+
+	"github.com/alecthomas/go-thrift/parser"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+// RootAnnotation marks a definition as used even if nothing in the project refers to it, eg.
+// because it is only used by application code.
+const RootAnnotation = "thriftlint.root"
+
+// CheckUnused checks for structs, unions, exceptions, enums, typedefs and constants that are not
+// reachable from any root of the project.
+//
+// Roots are services, definitions annotated with (thriftlint.root), and all definitions in files
+// whose path or base name matches one of the rootFiles glob patterns. Definitions referenced only
+// by other unused definitions are also unused. Constants can not be annotated, so can only be
+// exempted with rootFiles.
+//
+// As this is a project-wide check, all files that may refer to a definition should be linted
+// together.
+func CheckUnused(rootFiles []string) thriftlint.Check {
+	// Each CheckUnused has its own roots, so caches its reachable set under its own key.
+	key := new(int)
+	return thriftlint.MakeCheck("unused", func(project *thriftlint.Project, file *parser.Thrift, self interface{}) (messages thriftlint.Messages) {
+		index := project.Index()
+		definition := index.Definition(self)
+		if definition == nil || definition.Kind == thriftlint.ServiceKind {
+			return
+		}
+		reachable := project.Value(key, func() interface{} {
+			return reachableDefinitions(index, rootFiles)
+		}).(map[*thriftlint.Definition]bool)
+		if !reachable[definition] {
+			messages.Warning(self, "%s %s is never used", definition.Kind, definition.Name)
+		}
+		return
+	})
+}
+
+// Find all definitions reachable from the roots of the project.
+func reachableDefinitions(index *thriftlint.Index, rootFiles []string) map[*thriftlint.Definition]bool {
+	// Invert the index, mapping each definition to the definitions it refers to.
+	refersTo := map[*thriftlint.Definition][]*thriftlint.Definition{}
+	queue := []*thriftlint.Definition{}
+	for _, definition := range index.Definitions {
+		for _, ref := range index.References(definition) {
+			refersTo[ref.From] = append(refersTo[ref.From], definition)
+		}
+		if isRoot(definition, rootFiles) {
+			queue = append(queue, definition)
+		}
+	}
+	reachable := map[*thriftlint.Definition]bool{}
+	for len(queue) > 0 {
+		definition := queue[0]
+		queue = queue[1:]
+		if reachable[definition] {
+			continue
+		}
+		reachable[definition] = true
+		queue = append(queue, refersTo[definition]...)
+	}
+	return reachable
+}
+
+func isRoot(definition *thriftlint.Definition, rootFiles []string) bool {
+	if definition.Kind == thriftlint.ServiceKind {
+		return true
+	}
+	// Constants do not have annotations.
+	if definition.Kind != thriftlint.ConstantKind && thriftlint.AnnotationExists(definition.Node, RootAnnotation) {
+		return true
+	}
+	for _, pattern := range rootFiles {
+		if ok, _ := filepath.Match(pattern, definition.File.Filename); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, filepath.Base(definition.File.Filename)); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+func TestCheckUnused(t *testing.T) {
+	// Typedef and constant chains are used if their head is reachable from a service, and unused
+	// otherwise.
+	messages := lintSource(t, CheckUnused(nil), `
+typedef i64 UserId
+typedef UserId AccountId
+const i32 MAX_NAME = 64
+const i32 MAX_LENGTH = MAX_NAME
+enum Status { ACTIVE = 1 }
+struct User {
+  1: optional AccountId id
+  2: optional Status status
+  3: optional i32 length = MAX_LENGTH
+}
+exception NotFound {}
+service Users {
+  User getUser() throws (1: NotFound notFound)
+}
+
+typedef i64 Timestamp
+typedef Timestamp Created
+struct Audit {
+  1: optional Created created
+}
+const i32 LIMIT = 10
+const i32 UNUSED_LIMIT = LIMIT
+struct Kept {} (thriftlint.root)
+`)
+	require.Equal(t, []string{
+		`test.thrift:17:1:warning: typedef Timestamp is never used (unused)`,
+		`test.thrift:18:1:warning: typedef Created is never used (unused)`,
+		`test.thrift:19:8:warning: struct Audit is never used (unused)`,
+		`test.thrift:22:1:warning: constant LIMIT is never used (unused)`,
+		`test.thrift:23:1:warning: constant UNUSED_LIMIT is never used (unused)`,
+	}, messages)
+}
+
+func TestCheckUnusedAcrossFiles(t *testing.T) {
+	sources := map[string]string{
+		"service.thrift": `
+include "common.thrift"
+service Users {
+  common.User getUser()
+}
+`,
+		"common.thrift": `
+struct User {
+  1: optional string name
+}
+struct Orphan {}
+`,
+	}
+	messages := lintTest(t, thriftlint.Checks{CheckUnused(nil)}, "service.thrift", sources)
+	require.Equal(t, []string{
+		`common.thrift:5:8:warning: struct Orphan is never used (unused)`,
+	}, messages)
+}
+
+func TestCheckUnusedRootFiles(t *testing.T) {
+	sources := map[string]string{
+		"service.thrift": `
+include "models.thrift"
+include "constants.thrift"
+service Users {}
+`,
+		"models.thrift": `
+struct Model {}
+`,
+		"constants.thrift": `
+const i32 LIMIT = 10
+`,
+	}
+	// Constants can not be annotated, so are only exempted by file.
+	messages := lintTest(t, thriftlint.Checks{CheckUnused([]string{"constants.thrift", "/thriftlint-test/model*.thrift"})},
+		"service.thrift", sources)
+	require.Equal(t, []string{}, messages)
+	messages = lintTest(t, thriftlint.Checks{CheckUnused(nil)}, "service.thrift", sources)
+	require.Equal(t, []string{
+		`constants.thrift:2:0:warning: constant LIMIT is never used (unused)`,
+		`models.thrift:2:8:warning: struct Model is never used (unused)`,
+	}, messages)
+}
@@ -0,0 +1,27 @@
+package checks
+
+import (
+	"github.com/alecthomas/go-thrift/parser"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+// CheckValues checks that constant values and field defaults are valid for their declared types.
+func CheckValues() thriftlint.Check {
+	return thriftlint.MakeCheck("values", func(file *parser.Thrift, self interface{}) (messages thriftlint.Messages) {
+		switch node := self.(type) {
+		case *parser.Constant:
+			if err := thriftlint.TypeCheckValue(node.Type, node.Value, file); err != nil {
+				messages.Error(node, "invalid value for constant %s: %s", node.Name, err)
+			}
+		case *parser.Field:
+			if node.Default == nil {
+				return
+			}
+			if err := thriftlint.TypeCheckValue(node.Type, node.Default, file); err != nil {
+				messages.Error(node, "invalid default for %s: %s", node.Name, err)
+			}
+		}
+		return
+	})
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"gopkg.in/alecthomas/kingpin.v3-unstable"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+var (
+	changelogCommand    = kingpin.Command("changelog", "Summarise the changes between two versions of a schema tree.")
+	changelogOldFlag    = changelogCommand.Flag("old", "Root of the old schema tree.").Required().PlaceHolder("DIR").ExistingDir()
+	changelogNewFlag    = changelogCommand.Flag("new", "Root of the new schema tree.").Required().PlaceHolder("DIR").ExistingDir()
+	changelogFormatFlag = changelogCommand.Flag("format", "Output format.").Default("markdown").Enum("markdown", "json")
+)
+
+// Print a changelog in the format selected by --format.
+func changelog() {
+	changes, err := thriftlint.ChangelogDirs(*changelogOldFlag, *changelogNewFlag, *includeDirsFlag)
+	kingpin.FatalIfError(err, "")
+	if *changelogFormatFlag == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		kingpin.FatalIfError(encoder.Encode(changes), "")
+		return
+	}
+	kingpin.FatalIfError(changes.WriteMarkdown(os.Stdout), "")
+}
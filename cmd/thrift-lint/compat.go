@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/alecthomas/kingpin.v3-unstable"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+var (
+	compatCommand    = kingpin.Command("compat", "Detect breaking changes between two versions of a schema tree.")
+	compatOldFlag    = compatCommand.Flag("old", "Root of the old schema tree.").Required().PlaceHolder("DIR").ExistingDir()
+	compatNewFlag    = compatCommand.Flag("new", "Root of the new schema tree.").Required().PlaceHolder("DIR").ExistingDir()
+	compatFailOnFlag = compatCommand.Flag("fail-on-change", "Minimum compatibility class that results in a non-zero exit status.").Default("source-breaking").Enum("wire-breaking", "source-breaking", "never")
+)
+
+// Print the changes between two schema trees, exiting non-zero if any are breaking.
+func compat() {
+	changes, err := thriftlint.CompareDirs(*compatOldFlag, *compatNewFlag, *includeDirsFlag)
+	kingpin.FatalIfError(err, "")
+	for _, change := range changes {
+		pos := thriftlint.Pos(change.Object)
+		fmt.Printf("%s:%d:%d:%s: %s (compat)\n", change.File.Filename, pos.Line, pos.Col,
+			change.Compatibility, change.Message)
+	}
+	switch {
+	case *compatFailOnFlag == "never":
+	case changes.Max() == thriftlint.WireBreaking:
+		os.Exit(1)
+	case changes.Max() == thriftlint.SourceBreaking && *compatFailOnFlag == "source-breaking":
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/alecthomas/go-thrift/parser"
+	"gopkg.in/alecthomas/kingpin.v3-unstable"
+
+	"github.com/UrbanCompass/thriftlint"
+	"github.com/UrbanCompass/thriftlint/checks"
+)
+
+var (
+	coverageCommand    = kingpin.Command("coverage", "Print the percentage of definitions with doc comments in each source file.")
+	coverageSourcesArg = coverageCommand.Arg("sources", "Thrift sources to measure.").Required().ExistingFiles()
+)
+
+// Create the docs policy from the --docs-* flags.
+func docsPolicy() *checks.DocsPolicy {
+	return &checks.DocsPolicy{
+		Fields:        *docsFieldsFlag,
+		EnumValues:    *docsEnumValuesFlag,
+		StartWithName: *docsStartWithNameFlag,
+		Sentence:      *docsSentenceFlag,
+		MinLength:     *docsMinLengthFlag,
+	}
+}
+
+// Parse the --docs-link-pattern flags.
+func docsLinkPatterns() []*regexp.Regexp {
+	patterns := []*regexp.Regexp{}
+	for _, flag := range *docsLinkPatternFlag {
+		pattern, err := thriftlint.ParseDocLinkPattern(flag)
+		kingpin.FatalIfError(err, "")
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
+
+// Print the documentation coverage of each of paths, and in total.
+func docsCoverage(paths []string) {
+	files, err := thriftlint.Parse(*includeDirsFlag, paths)
+	kingpin.FatalIfError(err, "")
+	sources := map[string]*parser.Thrift{}
+	for _, source := range paths {
+		path, err := filepath.Abs(source)
+		kingpin.FatalIfError(err, "")
+		if file, ok := files[path]; ok {
+			sources[path] = file
+		}
+	}
+	total := &checks.DocsFileCoverage{}
+	fmt.Fprintf(os.Stdout, "Documentation coverage:\n")
+	for _, coverage := range checks.DocsCoverage(sources, docsPolicy()) {
+		total.Documented += coverage.Documented
+		total.Total += coverage.Total
+		fmt.Fprintf(os.Stdout, "  %5.1f%%  %4d/%-4d  %s\n", coverage.Percent(), coverage.Documented, coverage.Total,
+			coverage.File.Filename)
+	}
+	fmt.Fprintf(os.Stdout, "  %5.1f%%  %4d/%-4d  total\n", total.Percent(), total.Documented, total.Total)
+}
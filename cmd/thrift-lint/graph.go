@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/alecthomas/kingpin.v3-unstable"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+var (
+	graphCommand    = kingpin.Command("graph", "Export the include graph, or the type reference graph.")
+	graphTypesFlag  = graphCommand.Flag("types", "Export references between definitions rather than includes between files.").Bool()
+	graphFormatFlag = graphCommand.Flag("format", "Output format.").Default("dot").Enum("dot", "json")
+	graphSourcesArg = graphCommand.Arg("sources", "Thrift sources to graph.").Required().ExistingFiles()
+)
+
+// Print a dependency graph in the format selected by --format.
+func graph() {
+	files, err := thriftlint.Parse(*includeDirsFlag, *graphSourcesArg)
+	kingpin.FatalIfError(err, "")
+	var g *thriftlint.Graph
+	name := "includes"
+	if *graphTypesFlag {
+		g = thriftlint.TypeGraph(thriftlint.NewIndex(files))
+		name = "types"
+	} else {
+		g = relativeGraph(thriftlint.IncludeGraph(files))
+	}
+	if *graphFormatFlag == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		kingpin.FatalIfError(encoder.Encode(g), "")
+		return
+	}
+	kingpin.FatalIfError(g.WriteDot(os.Stdout, name), "")
+}
+
+// Rewrite the absolute filenames of an include graph relative to the working directory.
+func relativeGraph(g *thriftlint.Graph) *thriftlint.Graph {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return g
+	}
+	relative := func(path string) string {
+		if rel, err := filepath.Rel(cwd, path); err == nil {
+			return rel
+		}
+		return path
+	}
+	for i, node := range g.Nodes {
+		g.Nodes[i] = relative(node)
+	}
+	for _, edge := range g.Edges {
+		edge.From = relative(edge.From)
+		edge.To = relative(edge.To)
+	}
+	return g
+}
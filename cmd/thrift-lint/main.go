@@ -1,23 +1,58 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"gopkg.in/alecthomas/kingpin.v3-unstable"
 
 	"github.com/UrbanCompass/thriftlint"
 	"github.com/UrbanCompass/thriftlint/checks"
+	"github.com/UrbanCompass/thriftlint/lsp"
 )
 
 var (
-	includeDirsFlag = kingpin.Flag("include", "Include directories to search.").Short('I').PlaceHolder("DIR").ExistingDirs()
-	debugFlag       = kingpin.Flag("debug", "Enable debug logging.").Bool()
-	disableFlag     = kingpin.Flag("disable", "Linters to disable.").PlaceHolder("LINTER").Strings()
-	listFlag        = kingpin.Flag("list", "List linter checks.").Bool()
-	errorFlag       = kingpin.Flag("errors", "Only show errors.").Bool()
-	sourcesArgs     = kingpin.Arg("sources", "Thrift sources to lint.").Required().ExistingFiles()
+	includeDirsFlag         = kingpin.Flag("include", "Include directories to search.").Short('I').PlaceHolder("DIR").ExistingDirs()
+	debugFlag               = kingpin.Flag("debug", "Enable debug logging.").Bool()
+	disableFlag             = kingpin.Flag("disable", "Linters to disable.").PlaceHolder("LINTER").Strings()
+	listFlag                = kingpin.Flag("list", "List linter checks.").Bool()
+	errorFlag               = kingpin.Flag("errors", "Only show errors.").Bool()
+	statsFlag               = kingpin.Flag("stats", "Print summary statistics grouped by check, file and severity.").Bool()
+	failOnFlag              = kingpin.Flag("fail-on", "Minimum severity that results in a non-zero exit status.").Default("warning").Enum("error", "warning", "never")
+	maxWarningsFlag         = kingpin.Flag("max-warnings", "Fail if there are more than N warnings.").Default("-1").PlaceHolder("N").Int()
+	failOnCheckFlag         = kingpin.Flag("fail-on-check", "Checks that always result in a non-zero exit status.").PlaceHolder("CHECK,...").Strings()
+	unusedFlag              = kingpin.Flag("unused", "Report definitions that are never used.").Bool()
+	unusedRootFlag          = kingpin.Flag("unused-root", "Glob matching files whose definitions are always considered used by the unused check.").PlaceHolder("GLOB").Strings()
+	layeringAllowFlag       = kingpin.Flag("layering-allow", "Layering rule allowing files matching FROM to include only files matching TO.").PlaceHolder("FROM->TO").Strings()
+	layeringDenyFlag        = kingpin.Flag("layering-deny", "Layering rule forbidding files matching FROM from including files matching TO.").PlaceHolder("FROM->TO").Strings()
+	namespaceRequireFlag    = kingpin.Flag("namespace-require", "Namespace scope that every file must declare, eg. go.").PlaceHolder("SCOPE").Strings()
+	namespacePatternFlag    = kingpin.Flag("namespace-pattern", "Regular expression namespaces for SCOPE must match.").PlaceHolder("SCOPE=REGEX").Strings()
+	namespaceConsistentFlag = kingpin.Flag("namespace-consistent", "Namespace scopes whose namespaces must end in the same package name.").PlaceHolder("SCOPE,...").Strings()
+	namespacePathFlag       = kingpin.Flag("namespace-path", "Files declaring a SCOPE namespace starting with PREFIX must be in DIR followed by the rest of the namespace.").PlaceHolder("SCOPE:PREFIX=DIR").Strings()
+	enumPrefixFlag          = kingpin.Flag("enum-prefix", "Require enum value names to be prefixed with the enum name.").Bool()
+	enumZeroFlag            = kingpin.Flag("enum-zero", "Name the 0 value of every enum must have, optionally prefixed with the enum name.").PlaceHolder("NAME").Strings()
+	defaultsAllowFlag       = kingpin.Flag("defaults-allow", "Where default values are allowed: scalar, enum, container, struct, optional, union or exception. Defaults are not allowed anywhere if unset.").PlaceHolder("WHERE,...").String()
+	docsRequiredFlag        = kingpin.Flag("docs-required", "Require doc comments on services, methods, structs, unions, exceptions and enums.").Bool()
+	docsFieldsFlag          = kingpin.Flag("docs-fields", "Require doc comments on struct, union and exception fields.").Bool()
+	docsEnumValuesFlag      = kingpin.Flag("docs-enum-values", "Require doc comments on enum values.").Bool()
+	docsStartWithNameFlag   = kingpin.Flag("docs-start-with-name", "Require doc comments to start with the name of what they document.").Bool()
+	docsSentenceFlag        = kingpin.Flag("docs-sentence", "Require doc comments to be complete sentences.").Bool()
+	docsMinLengthFlag       = kingpin.Flag("docs-min-length", "Minimum length of doc comments.").PlaceHolder("N").Int()
+	docsLinkPatternFlag     = kingpin.Flag("docs-link-pattern", "Regular expression matching references to other definitions in doc comments, whose first group is the referenced symbol.").PlaceHolder("REGEX").Strings()
+	docsCoverageFlag        = kingpin.Flag("docs-coverage", "Print the percentage of definitions with doc comments in each file after linting.").Bool()
+
+	lintCommand       = kingpin.Command("lint", "Lint Thrift sources.").Default()
+	formatFlag        = lintCommand.Flag("format", "Output format.").Default("text").Enum("text", "template")
+	templateFlag      = lintCommand.Flag("template", "Go template used to format each message when --format=template.").PlaceHolder("TEMPLATE").String()
+	watchFlag         = lintCommand.Flag("watch", "Keep running, re-linting files as they change.").Bool()
+	watchIntervalFlag = lintCommand.Flag("watch-interval", "Interval between polls for changes when watching.").Default("1s").Duration()
+	watchClearFlag    = lintCommand.Flag("watch-clear", "Clear the screen and reprint all messages on change, rather than printing differences.").Bool()
+	sourcesArgs       = lintCommand.Arg("sources", "Thrift sources to lint.").Required().ExistingFiles()
+
+	lspCommand = kingpin.Command("lsp", "Run a Language Server Protocol server over stdio.")
 )
 
 func main() {
@@ -25,16 +60,45 @@ func main() {
 
 For details, please refer to https://github.com/UrbanCompass/thriftlint
 `
-	kingpin.Parse()
+	command := kingpin.Parse()
 	checkers := thriftlint.Checks{
 		checks.CheckIndentation(),
 		checks.CheckNames(nil, nil),
 		checks.CheckOptional(),
-		checks.CheckDefaultValues(),
+		checks.CheckDefaultValuesPolicy(defaultsPolicy()),
 		checks.CheckEnumSequence(),
+		checks.CheckEnumDuplicates(),
+		checks.CheckEnumZero(*enumZeroFlag),
+		checks.CheckEnumPrefix(),
 		checks.CheckMapKeys(),
 		checks.CheckTypeReferences(),
+		checks.CheckThrowsTypes(),
+		checks.CheckExtendsTypes(),
+		checks.CheckMethodOneway(),
+		checks.CheckMethodThrows(),
+		checks.CheckMethodArguments(),
+		checks.CheckMethodInherited(),
+		checks.CheckConstantReferences(),
+		checks.CheckValues(),
 		checks.CheckStructFieldOrder(),
+		checks.CheckFieldIDDuplicates(),
+		checks.CheckFieldIDPositive(),
+		checks.CheckFieldIDRange(),
+		checks.CheckReserved(),
+		checks.CheckRecursion(),
+		checks.CheckUnused(*unusedRootFlag),
+		checks.CheckIncludesUnused(),
+		checks.CheckIncludesDuplicate(),
+		checks.CheckIncludesCycle(),
+		checks.CheckIncludesLayering(layeringRules()),
+		checks.CheckNamespacesRequired(*namespaceRequireFlag),
+		checks.CheckNamespacesPattern(namespacePatterns()),
+		checks.CheckNamespacesConsistent(namespaceGroups()),
+		checks.CheckNamespacesPath(namespacePaths()),
+		checks.CheckNamespacesConflict(),
+		checks.CheckDocsMissing(docsPolicy()),
+		checks.CheckDocsStyle(docsPolicy()),
+		checks.CheckDocsLinks(docsLinkPatterns()),
 	}
 	checkers = append(checkers, checks.CheckAnnotations(nil, checkers))
 
@@ -45,27 +109,183 @@ For details, please refer to https://github.com/UrbanCompass/thriftlint
 		return
 	}
 
+	// Checks that are disabled unless enabled by their flag.
+	optIn := map[string]bool{
+		"unused":       *unusedFlag,
+		"docs.missing": *docsRequiredFlag,
+		"enum.prefix":  *enumPrefixFlag,
+	}
+	disabled := *disableFlag
+	for id, enabled := range optIn {
+		if !enabled {
+			disabled = append(disabled, id)
+		}
+	}
 	options := []thriftlint.Option{
 		thriftlint.WithIncludeDirs(*includeDirsFlag...),
-		thriftlint.Disable(*disableFlag...),
+		thriftlint.Disable(disabled...),
 	}
 	if *debugFlag {
-		logger := log.New(os.Stdout, "debug: ", 0)
+		// stdout is used by the LSP protocol.
+		out := os.Stdout
+		if command == lspCommand.FullCommand() {
+			out = os.Stderr
+		}
+		logger := log.New(out, "debug: ", 0)
 		options = append(options, thriftlint.WithLogger(logger))
 	}
-	linter, err := thriftlint.New(checkers, options...)
-	kingpin.FatalIfError(err, "")
-	messages, err := linter.Lint(*sourcesArgs)
-	kingpin.FatalIfError(err, "")
-	status := 0
+
+	switch command {
+	case lspCommand.FullCommand():
+		server := lsp.NewServer(checkers, options...)
+		kingpin.FatalIfError(server.Serve(os.Stdin, os.Stdout), "")
+
+	case refsCommand.FullCommand():
+		refs()
+
+	case compatCommand.FullCommand():
+		compat()
+
+	case changelogCommand.FullCommand():
+		changelog()
+
+	case graphCommand.FullCommand():
+		graph()
+
+	case registryAddCommand.FullCommand():
+		registryAdd()
+
+	case registryCheckCommand.FullCommand():
+		registryCheck()
+
+	case coverageCommand.FullCommand():
+		docsCoverage(*coverageSourcesArg)
+
+	default:
+		lint(checkers, options)
+	}
+}
+
+// Parse the --defaults-allow policy.
+func defaultsPolicy() *checks.DefaultsPolicy {
+	if *defaultsAllowFlag == "" {
+		return nil
+	}
+	policy, err := checks.ParseDefaultsPolicy(*defaultsAllowFlag)
+	kingpin.FatalIfError(err, "invalid --defaults-allow")
+	return policy
+}
+
+// Parse the --layering-allow and --layering-deny rules.
+func layeringRules() []*checks.LayeringRule {
+	rules := []*checks.LayeringRule{}
+	for _, flag := range []struct {
+		allow bool
+		rules []string
+	}{{true, *layeringAllowFlag}, {false, *layeringDenyFlag}} {
+		for _, text := range flag.rules {
+			rule, err := checks.ParseLayeringRule(flag.allow, text)
+			kingpin.FatalIfError(err, "")
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// Parse --namespace-pattern flags.
+func namespacePatterns() []*checks.NamespacePattern {
+	patterns := []*checks.NamespacePattern{}
+	for _, flag := range *namespacePatternFlag {
+		pattern, err := checks.ParseNamespacePattern(flag)
+		kingpin.FatalIfError(err, "")
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
+
+// Parse --namespace-consistent flags into groups of scopes.
+func namespaceGroups() [][]string {
+	groups := [][]string{}
+	for _, flag := range *namespaceConsistentFlag {
+		groups = append(groups, strings.Split(flag, ","))
+	}
+	return groups
+}
+
+// Parse --namespace-path flags.
+func namespacePaths() []*checks.NamespacePathMapping {
+	mappings := []*checks.NamespacePathMapping{}
+	for _, flag := range *namespacePathFlag {
+		mapping, err := checks.ParseNamespacePathMapping(flag)
+		kingpin.FatalIfError(err, "")
+		mappings = append(mappings, mapping)
+	}
+	return mappings
+}
+
+// Create a function that formats messages according to --format.
+func newFormatter() func(msg *thriftlint.Message) string {
+	if *formatFlag == "template" {
+		if *templateFlag == "" {
+			kingpin.Fatalf("--template is required with --format=template")
+		}
+		tmpl, err := thriftlint.NewMessageTemplate(*templateFlag)
+		kingpin.FatalIfError(err, "invalid --template")
+		return func(msg *thriftlint.Message) string {
+			w := &bytes.Buffer{}
+			kingpin.FatalIfError(tmpl.Execute(w, msg), "")
+			return w.String()
+		}
+	}
+	return formatText
+}
+
+// Format a message as "<file>:<line>:<col>:<severity>: <message> (<check>)".
+func formatText(msg *thriftlint.Message) string {
+	pos := thriftlint.Pos(msg.Object)
+	return fmt.Sprintf("%s:%d:%d:%s: %s (%s)\n", msg.File.Filename, pos.Line, pos.Col,
+		msg.Severity, msg.Message, msg.Checker)
+}
+
+// Filter messages according to --errors.
+func filterMessages(messages thriftlint.Messages) thriftlint.Messages {
+	out := thriftlint.Messages{}
 	for _, msg := range messages {
 		if *errorFlag && msg.Severity != thriftlint.Error {
 			continue
 		}
-		pos := thriftlint.Pos(msg.Object)
-		fmt.Fprintf(os.Stderr, "%s:%d:%d:%s: %s (%s)\n", msg.File.Filename, pos.Line, pos.Col,
-			msg.Severity, msg.Message, msg.Checker)
-		status |= 1 << uint(msg.Severity)
+		out = append(out, msg)
+	}
+	return out
+}
+
+func lint(checkers thriftlint.Checks, options []thriftlint.Option) {
+	format := newFormatter()
+	linter, err := thriftlint.New(checkers, options...)
+	kingpin.FatalIfError(err, "")
+	if *watchFlag {
+		kingpin.FatalIfError(watch(linter, format), "")
+		return
+	}
+	messages, err := linter.Lint(*sourcesArgs)
+	kingpin.FatalIfError(err, "")
+	policy, err := thriftlint.NewExitPolicy(*failOnFlag, *maxWarningsFlag, *failOnCheckFlag)
+	kingpin.FatalIfError(err, "")
+	if *errorFlag && policy.FailOn != nil {
+		// Warnings that are not shown only fail through --max-warnings and --fail-on-check.
+		severity := thriftlint.Error
+		policy.FailOn = &severity
+	}
+	summary := thriftlint.NewStats()
+	for _, msg := range filterMessages(messages) {
+		summary.Add(msg)
+		fmt.Fprint(os.Stderr, format(msg))
+	}
+	if *statsFlag {
+		summary.Write(os.Stdout)
+	}
+	if *docsCoverageFlag {
+		docsCoverage(*sourcesArgs)
 	}
-	os.Exit(status)
+	os.Exit(policy.Status(messages))
 }
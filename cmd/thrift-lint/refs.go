@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+
+	"gopkg.in/alecthomas/kingpin.v3-unstable"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+var (
+	refsCommand    = kingpin.Command("refs", "Find references to a definition.")
+	refsSymbolArg  = refsCommand.Arg("symbol", "Definition to find references to, eg. User or common.User.").Required().String()
+	refsSourcesArg = refsCommand.Arg("sources", "Thrift sources to search.").Required().ExistingFiles()
+)
+
+// Print references to a symbol, one per line, in the same format as lint messages with info
+// severity and the check "refs".
+func refs() {
+	files, err := thriftlint.Parse(*includeDirsFlag, *refsSourcesArg)
+	kingpin.FatalIfError(err, "")
+	index := thriftlint.NewIndex(files)
+	definitions := index.Find(*refsSymbolArg)
+	if len(definitions) == 0 {
+		kingpin.Fatalf("unknown symbol %q", *refsSymbolArg)
+	}
+	for _, definition := range definitions {
+		for _, ref := range index.References(definition) {
+			fmt.Print(formatText(&thriftlint.Message{
+				File:     ref.File,
+				Checker:  "refs",
+				Severity: thriftlint.Info,
+				Object:   ref,
+				Message: fmt.Sprintf("reference to %s %s from %s", definition.Kind, definition.QualifiedName(),
+					ref.From.Name),
+			}))
+		}
+	}
+}
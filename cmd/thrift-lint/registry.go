@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/alecthomas/kingpin.v3-unstable"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+var (
+	registryCommand     = kingpin.Command("registry", "Record and check released schema versions.")
+	registryDirFlag     = registryCommand.Flag("registry", "Registry directory.").Required().PlaceHolder("DIR").String()
+	registrySubjectFlag = registryCommand.Flag("subject", "Name of the schema in the registry, eg. a service name.").Required().String()
+	registryRootFlag    = registryCommand.Flag("root", "Root of the schema tree.").Default(".").PlaceHolder("DIR").ExistingDir()
+
+	registryAddCommand  = registryCommand.Command("add", "Record the schema tree as a new version.")
+	registryVersionFlag = registryAddCommand.Flag("version", "Version label to record.").Required().String()
+
+	registryCheckCommand = registryCommand.Command("check", "Check the schema tree against recorded versions.")
+	registryModeFlag     = registryCheckCommand.Flag("mode", "Compatibility mode.").Default("backward").Enum("backward", "forward", "full", "transitive")
+)
+
+func registryAdd() {
+	registry := &thriftlint.Registry{Dir: *registryDirFlag}
+	err := registry.Add(*registrySubjectFlag, *registryVersionFlag, *registryRootFlag, *includeDirsFlag)
+	kingpin.FatalIfError(err, "")
+}
+
+// Print wire-breaking changes against recorded versions, exiting non-zero if there are any.
+func registryCheck() {
+	registry := &thriftlint.Registry{Dir: *registryDirFlag}
+	mode, err := thriftlint.ParseCompatibilityMode(*registryModeFlag)
+	kingpin.FatalIfError(err, "")
+	checks, err := registry.Check(*registrySubjectFlag, *registryRootFlag, mode, *includeDirsFlag)
+	kingpin.FatalIfError(err, "")
+	failed := false
+	for _, check := range checks {
+		for _, change := range check.Changes {
+			if change.Compatibility != thriftlint.WireBreaking {
+				continue
+			}
+			failed = true
+			pos := thriftlint.Pos(change.Object)
+			fmt.Printf("%s:%d:%d:%s: not %s compatible with %s: %s (registry)\n", change.File.Filename,
+				pos.Line, pos.Col, change.Compatibility, check.Direction, check.Version, change.Message)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
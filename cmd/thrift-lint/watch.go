@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+// Watch the source and include directories for changes to .thrift files, re-linting changed
+// files and the files that include them.
+func watch(linter *thriftlint.Linter, format func(msg *thriftlint.Message) string) error {
+	w, err := thriftlint.NewWatcher(linter, *sourcesArgs, func(msg *thriftlint.Message) string {
+		if len(filterMessages(thriftlint.Messages{msg})) == 0 {
+			return ""
+		}
+		return format(msg)
+	})
+	if err != nil {
+		return err
+	}
+	dirs := w.Dirs()
+	previous := scan(dirs)
+	relint(w, nil)
+	for {
+		time.Sleep(*watchIntervalFlag)
+		mtimes := scan(dirs)
+		changed := []string{}
+		for path, mtime := range mtimes {
+			if mtime0, ok := previous[path]; !ok || !mtime0.Equal(mtime) {
+				changed = append(changed, path)
+			}
+		}
+		for path := range previous {
+			if _, ok := mtimes[path]; !ok {
+				changed = append(changed, path)
+			}
+		}
+		previous = mtimes
+		if len(changed) > 0 {
+			relint(w, changed)
+		}
+	}
+}
+
+// Re-lint and print either all messages or the differences, according to --watch-clear.
+func relint(w *thriftlint.Watcher, changed []string) {
+	added, removed, err := w.Lint(changed)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "thrift-lint: error: %s\n", err)
+		return
+	}
+	if *watchClearFlag || changed == nil {
+		if changed != nil {
+			// Clear the screen and move the cursor to the top left.
+			fmt.Fprint(os.Stderr, "\033[H\033[2J")
+		}
+		for _, line := range w.Lines() {
+			fmt.Fprint(os.Stderr, line)
+		}
+		return
+	}
+	for _, line := range added {
+		fmt.Fprint(os.Stderr, "+ "+line)
+	}
+	for _, line := range removed {
+		fmt.Fprint(os.Stderr, "- "+line)
+	}
+}
+
+// Scan dirs for .thrift files and their modification times.
+func scan(dirs []string) map[string]time.Time {
+	mtimes := map[string]time.Time{}
+	for _, dir := range dirs {
+		_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || filepath.Ext(path) != ".thrift" {
+				return nil
+			}
+			if abs, err := filepath.Abs(path); err == nil {
+				mtimes[abs] = info.ModTime()
+			}
+			return nil
+		})
+	}
+	return mtimes
+}
@@ -0,0 +1,697 @@
+package thriftlint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/go-thrift/parser"
+)
+
+// Compatibility classification of a schema change.
+type Compatibility int
+
+// Compatibility classifications, in increasing order of severity.
+const (
+	// Safe changes are compatible on the wire and in generated code.
+	Safe Compatibility = iota
+	// SourceBreaking changes are compatible on the wire, but break code using the generated code.
+	SourceBreaking
+	// WireBreaking changes break communication between old and new versions.
+	WireBreaking
+)
+
+func (c Compatibility) String() string {
+	switch c {
+	case SourceBreaking:
+		return "source-breaking"
+	case WireBreaking:
+		return "wire-breaking"
+	}
+	return "safe"
+}
+
+// ChangeKind is the kind of a schema change.
+type ChangeKind int
+
+// Kinds of schema change.
+const (
+	Added ChangeKind = iota
+	Removed
+	Modified
+)
+
+func (c ChangeKind) String() string {
+	switch c {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	}
+	return "modified"
+}
+
+// Change is a single difference between two versions of a schema.
+type Change struct {
+	Kind          ChangeKind
+	Compatibility Compatibility
+	// Definition is the qualified name of the changed definition, eg. "common/user.User".
+	Definition string
+	// DefinitionKind is the kind of the changed definition.
+	DefinitionKind Kind
+	// Member of the definition that changed, eg. a field, enum value or method name, if any.
+	Member string
+	// File and Object the change applies to. These are from the new schema, except for removals.
+	File    *parser.Thrift
+	Object  interface{}
+	Message string
+}
+
+// Changes is a list of schema changes.
+type Changes []*Change
+
+// Max returns the most severe Compatibility of the changes.
+func (c Changes) Max() Compatibility {
+	max := Safe
+	for _, change := range c {
+		if change.Compatibility > max {
+			max = change.Compatibility
+		}
+	}
+	return max
+}
+
+// ParseDir parses all .thrift files under dir, recursively.
+//
+// dir is searched for includes, followed by includeDirs.
+func ParseDir(dir string, includeDirs []string) (map[string]*parser.Thrift, error) {
+	return parseDirExcluding(dir, includeDirs, "")
+}
+
+// parseDirExcluding is ParseDir, skipping the directory exclude if it is under dir.
+func parseDirExcluding(dir string, includeDirs []string, exclude string) (map[string]*parser.Thrift, error) {
+	if exclude != "" {
+		var err error
+		if exclude, err = filepath.Abs(exclude); err != nil {
+			return nil, err
+		}
+	}
+	sources := []string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && exclude != "" {
+			if abs, err := filepath.Abs(path); err == nil && abs == exclude {
+				return filepath.SkipDir
+			}
+		}
+		if !info.IsDir() && filepath.Ext(path) == ".thrift" {
+			sources = append(sources, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no .thrift files found in %s", dir)
+	}
+	return Parse(append([]string{dir}, includeDirs...), sources)
+}
+
+// CompareDirs parses the schema trees under oldDir and newDir and compares them. See Compare.
+//
+// Files found in includeDirs rather than the trees are matched by their path relative to the
+// include directory.
+func CompareDirs(oldDir, newDir string, includeDirs []string) (Changes, error) {
+	old, err := ParseDir(oldDir, includeDirs)
+	if err != nil {
+		return nil, err
+	}
+	newer, err := ParseDir(newDir, includeDirs)
+	if err != nil {
+		return nil, err
+	}
+	return compareTrees(&schemaTree{oldDir, includeDirs, old}, &schemaTree{newDir, includeDirs, newer}, false, Full)
+}
+
+// Compare two versions of a schema tree, as returned by Parse.
+//
+// Definitions are matched by qualified name, which is the path of their file relative to its tree
+// root, without extension, followed by the definition name. eg. "common/user.User". Definitions in
+// files outside the tree use the absolute path of the file instead. Fields are matched by ID, enum
+// values and methods by name.
+//
+// Changes are classified as wire-breaking if they break either old readers of new data or new
+// readers of old data.
+func Compare(oldRoot string, old map[string]*parser.Thrift, newRoot string, newer map[string]*parser.Thrift) (Changes, error) {
+	return compareTrees(&schemaTree{oldRoot, nil, old}, &schemaTree{newRoot, nil, newer}, false, Full)
+}
+
+// A version of a schema tree.
+type schemaTree struct {
+	root string
+	// Include directories outside root. Files found in them are named relative to the include
+	// directory, so that trees using different copies of the same includes match.
+	includeDirs []string
+	files       map[string]*parser.Thrift
+}
+
+// Compare two trees. direction is Backward to only classify changes as wire-breaking if new readers
+// can not read old data, Forward if old readers can not read new data, and Full for both.
+func compareTrees(old, newer *schemaTree, details bool, direction CompatibilityMode) (Changes, error) {
+	c := &comparer{details: details, direction: direction}
+	var err error
+	if c.oldFiles, err = old.relativeFiles(); err != nil {
+		return nil, err
+	}
+	if c.newFiles, err = newer.relativeFiles(); err != nil {
+		return nil, err
+	}
+	c.compare()
+	return c.changes, nil
+}
+
+// Map files by their path relative to the tree root, without extension.
+func (t *schemaTree) relativeFiles() (map[string]*parser.Thrift, error) {
+	root, err := filepath.Abs(t.root)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]*parser.Thrift{}
+	outside := map[string]*parser.Thrift{}
+	for path, file := range t.files {
+		if rel, ok := relativePath(root, path); ok {
+			out[filePath(rel)] = file
+			continue
+		}
+		// Files outside the tree are identified by their path relative to the include directory they
+		// were found in, or failing that by absolute path, so that they match between trees.
+		name := path
+		if rel, ok := includeRelativePath(t.includeDirs, path); ok {
+			name = rel
+		}
+		outside[filePath(name)] = file
+	}
+	// Files in the tree take precedence.
+	for name, file := range outside {
+		if _, ok := out[name]; !ok {
+			out[name] = file
+		}
+	}
+	return out, nil
+}
+
+// Return path relative to dir, if it is inside dir.
+func relativePath(dir, path string) (string, bool) {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// Return path relative to the first of includeDirs containing it.
+func includeRelativePath(includeDirs []string, path string) (string, bool) {
+	for _, dir := range includeDirs {
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			continue
+		}
+		if rel, ok := relativePath(dir, path); ok {
+			return rel, true
+		}
+	}
+	return "", false
+}
+
+// Strip the extension of a path, using forward slashes.
+func filePath(path string) string {
+	return filepath.ToSlash(strings.TrimSuffix(path, filepath.Ext(path)))
+}
+
+type comparer struct {
+	oldFiles map[string]*parser.Thrift
+	newFiles map[string]*parser.Thrift
+	changes  Changes
+	// Also report changes to annotations and doc comments.
+	details bool
+	// Backward, Forward or Full. See compareTrees.
+	direction CompatibilityMode
+	// Reverse mapping of file to relative path, for both trees.
+	paths map[*parser.Thrift]string
+}
+
+// A definition being compared.
+type compared struct {
+	name string
+	kind Kind
+	file *parser.Thrift
+	node interface{}
+}
+
+func (c *comparer) add(kind ChangeKind, compatibility Compatibility, definition *compared, member string,
+	file *parser.Thrift, object interface{}, format string, args ...interface{}) {
+	c.changes = append(c.changes, &Change{
+		Kind:           kind,
+		Compatibility:  compatibility,
+		Definition:     definition.name,
+		DefinitionKind: definition.kind,
+		Member:         member,
+		File:           file,
+		Object:         object,
+		Message:        fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *comparer) compare() {
+	c.paths = map[*parser.Thrift]string{}
+	for path, file := range c.oldFiles {
+		c.paths[file] = path
+	}
+	for path, file := range c.newFiles {
+		c.paths[file] = path
+	}
+	old := c.definitions(c.oldFiles)
+	newer := c.definitions(c.newFiles)
+	names := map[string]bool{}
+	for name := range old {
+		names[name] = true
+	}
+	for name := range newer {
+		names[name] = true
+	}
+	for _, name := range sortedNames(names) {
+		o, n := old[name], newer[name]
+		switch {
+		case n == nil:
+			compatibility := SourceBreaking
+			if o.kind == ServiceKind {
+				compatibility = WireBreaking
+			}
+			c.add(Removed, compatibility, o, "", o.file, o.node, "%s %s was removed", o.kind, name)
+		case o == nil:
+			c.add(Added, Safe, n, "", n.file, n.node, "%s %s was added", n.kind, name)
+		case o.kind != n.kind:
+			c.add(Modified, WireBreaking, n, "", n.file, n.node, "%s changed from %s to %s", name, o.kind, n.kind)
+		default:
+			c.compareDetails(n, "", fmt.Sprintf("%s %s", n.kind, name), o.node, n.node)
+			c.compareDefinition(o, n)
+		}
+	}
+}
+
+// Collect the definitions of a tree by qualified name.
+func (c *comparer) definitions(files map[string]*parser.Thrift) map[string]*compared {
+	out := map[string]*compared{}
+	for path, file := range files {
+		add := func(name string, kind Kind, node interface{}) {
+			qualified := path + "." + name
+			out[qualified] = &compared{name: qualified, kind: kind, file: file, node: node}
+		}
+		for name, node := range file.Typedefs {
+			add(name, TypedefKind, node)
+		}
+		for name, node := range file.Constants {
+			add(name, ConstantKind, node)
+		}
+		for name, node := range file.Enums {
+			add(name, EnumKind, node)
+		}
+		for name, node := range file.Structs {
+			add(name, StructKind, node)
+		}
+		for name, node := range file.Unions {
+			add(name, UnionKind, node)
+		}
+		for name, node := range file.Exceptions {
+			add(name, ExceptionKind, node)
+		}
+		for name, node := range file.Services {
+			add(name, ServiceKind, node)
+		}
+	}
+	return out
+}
+
+func (c *comparer) compareDefinition(o, n *compared) {
+	switch onode := o.node.(type) {
+	case *parser.Struct:
+		c.compareFields(n, "field", o.file, onode.Fields, n.file, n.node.(*parser.Struct).Fields, true)
+
+	case *parser.Enum:
+		c.compareEnums(o, n)
+
+	case *parser.Typedef:
+		nnode := n.node.(*parser.Typedef)
+		if compatibility, changed := c.compareTypes(o.file, onode.Type, n.file, nnode.Type); changed {
+			c.add(Modified, compatibility, n, "", n.file, nnode, "typedef %s changed from %s to %s", n.name,
+				onode.Type, nnode.Type)
+		}
+
+	case *parser.Constant:
+		nnode := n.node.(*parser.Constant)
+		if compatibility, changed := c.compareTypes(o.file, onode.Type, n.file, nnode.Type); changed {
+			c.add(Modified, compatibility, n, "", n.file, nnode, "type of constant %s changed from %s to %s",
+				n.name, onode.Type, nnode.Type)
+		} else if fmt.Sprint(onode.Value) != fmt.Sprint(nnode.Value) {
+			c.add(Modified, Safe, n, "", n.file, nnode, "value of constant %s changed", n.name)
+		}
+
+	case *parser.Service:
+		c.compareServices(o, n, onode, n.node.(*parser.Service))
+	}
+}
+
+func (c *comparer) compareEnums(oc, n *compared) {
+	o, e := oc.node.(*parser.Enum), n.node.(*parser.Enum)
+	oldByValue := map[int]*parser.EnumValue{}
+	for _, v := range o.Values {
+		oldByValue[v.Value] = v
+	}
+	newByValue := map[int]*parser.EnumValue{}
+	for _, v := range e.Values {
+		newByValue[v.Value] = v
+	}
+	for _, name := range sortedEnumValueNames(o.Values) {
+		ov := o.Values[name]
+		nv, ok := e.Values[name]
+		switch {
+		case ok:
+			if nv.Value != ov.Value {
+				c.add(Modified, WireBreaking, n, name, n.file, nv, "value of %s.%s changed from %d to %d", n.name,
+					name, ov.Value, nv.Value)
+			}
+			c.compareDetails(n, name, n.name+"."+name, ov, nv)
+		case !ok && newByValue[ov.Value] != nil && o.Values[newByValue[ov.Value].Name] == nil:
+			renamed := newByValue[ov.Value]
+			c.add(Modified, SourceBreaking, n, name, n.file, renamed, "%s.%s was renamed to %s", n.name, name,
+				renamed.Name)
+		case !ok:
+			// New readers can not read the value in old data.
+			compatibility := WireBreaking
+			if c.direction == Forward {
+				compatibility = SourceBreaking
+			}
+			c.add(Removed, compatibility, n, name, oc.file, o, "%s.%s (%d) was removed", n.name, name, ov.Value)
+		}
+	}
+	for _, name := range sortedEnumValueNames(e.Values) {
+		nv := e.Values[name]
+		if _, ok := o.Values[name]; ok {
+			continue
+		}
+		// Renames are reported above.
+		if ov := oldByValue[nv.Value]; ov != nil && e.Values[ov.Name] == nil {
+			continue
+		}
+		c.add(Added, Safe, n, name, n.file, nv, "%s.%s (%d) was added", n.name, name, nv.Value)
+	}
+}
+
+func (c *comparer) compareServices(o, n *compared, oldSvc, newSvc *parser.Service) {
+	if oldSvc.Extends != newSvc.Extends {
+		c.add(Modified, WireBreaking, n, "", n.file, newSvc, "service %s changed from extending %q to %q", n.name,
+			oldSvc.Extends, newSvc.Extends)
+	}
+	for _, name := range sortedMethodNames(oldSvc.Methods) {
+		om := oldSvc.Methods[name]
+		nm, ok := newSvc.Methods[name]
+		if !ok {
+			c.add(Removed, WireBreaking, n, name, o.file, om, "method %s.%s was removed", n.name, name)
+			continue
+		}
+		member := fmt.Sprintf("%s.%s", n.name, name)
+		c.compareDetails(n, name, "method "+member, om, nm)
+		if om.Oneway != nm.Oneway {
+			c.add(Modified, WireBreaking, n, name, n.file, nm, "oneway of method %s changed from %v to %v",
+				member, om.Oneway, nm.Oneway)
+		}
+		switch {
+		case om.ReturnType == nil && nm.ReturnType == nil:
+		case om.ReturnType == nil || nm.ReturnType == nil:
+			c.add(Modified, WireBreaking, n, name, n.file, nm, "return type of method %s changed from %s to %s",
+				member, typeString(om.ReturnType), typeString(nm.ReturnType))
+		default:
+			if compatibility, changed := c.compareTypes(o.file, om.ReturnType, n.file, nm.ReturnType); changed {
+				c.add(Modified, compatibility, n, name, n.file, nm, "return type of method %s changed from %s to %s",
+					member, om.ReturnType, nm.ReturnType)
+			}
+		}
+		c.compareFields(n, "argument of "+member, o.file, om.Arguments, n.file, nm.Arguments, false)
+		c.compareExceptions(n, member, o.file, om.Exceptions, n.file, nm.Exceptions)
+	}
+	for _, name := range sortedMethodNames(newSvc.Methods) {
+		if _, ok := oldSvc.Methods[name]; !ok {
+			c.add(Added, Safe, n, name, n.file, newSvc.Methods[name], "method %s.%s was added", n.name, name)
+		}
+	}
+}
+
+func (c *comparer) compareExceptions(n *compared, member string, ofile *parser.Thrift, old []*parser.Field,
+	nfile *parser.Thrift, newer []*parser.Field) {
+	oldByID := fieldsByID(old)
+	newByID := fieldsByID(newer)
+	for _, of := range old {
+		nf, ok := newByID[of.ID]
+		if !ok {
+			c.add(Removed, WireBreaking, n, member, ofile, of, "exception %d %q was removed from %s", of.ID, of.Name, member)
+			continue
+		}
+		if compatibility, changed := c.compareTypes(ofile, of.Type, nfile, nf.Type); changed {
+			c.add(Modified, compatibility, n, member, nfile, nf, "type of exception %d %q of %s changed from %s to %s",
+				of.ID, of.Name, member, of.Type, nf.Type)
+		}
+	}
+	for _, nf := range newer {
+		if _, ok := oldByID[nf.ID]; !ok {
+			c.add(Added, SourceBreaking, n, member, nfile, nf, "exception %d %q was added to %s", nf.ID, nf.Name, member)
+		}
+	}
+}
+
+// Compare fields matched by ID. If requiredness is false, as for method arguments, the optional
+// flag of the fields is ignored.
+func (c *comparer) compareFields(n *compared, what string, ofile *parser.Thrift, old []*parser.Field,
+	nfile *parser.Thrift, newer []*parser.Field, requiredness bool) {
+	oldByID := fieldsByID(old)
+	newByID := fieldsByID(newer)
+	oldByName := map[string]*parser.Field{}
+	for _, f := range old {
+		oldByName[f.Name] = f
+	}
+	newByName := map[string]*parser.Field{}
+	for _, f := range newer {
+		newByName[f.Name] = f
+	}
+	describe := func(f *parser.Field) string { return fmt.Sprintf("%s %d %q of %s", what, f.ID, f.Name, n.name) }
+
+	for _, of := range old {
+		nf, ok := newByID[of.ID]
+		if !ok {
+			if renumbered := newByName[of.Name]; renumbered != nil && oldByID[renumbered.ID] == nil {
+				c.add(Modified, WireBreaking, n, of.Name, nfile, renumbered, "%s was renumbered to %d", describe(of),
+					renumbered.ID)
+				continue
+			}
+			// Old readers require the field in new data.
+			compatibility := SourceBreaking
+			if requiredness && !of.Optional && c.direction != Backward {
+				compatibility = WireBreaking
+			}
+			c.add(Removed, compatibility, n, of.Name, ofile, of, "%s was removed", describe(of))
+			continue
+		}
+		c.compareDetails(n, nf.Name, describe(of), of, nf)
+		if of.Name != nf.Name {
+			c.add(Modified, SourceBreaking, n, nf.Name, nfile, nf, "%s was renamed to %q", describe(of), nf.Name)
+		}
+		if compatibility, changed := c.compareTypes(ofile, of.Type, nfile, nf.Type); changed {
+			c.add(Modified, compatibility, n, nf.Name, nfile, nf, "type of %s changed from %s to %s", describe(of),
+				of.Type, nf.Type)
+		}
+		if requiredness && of.Optional != nf.Optional {
+			from, to := "optional", "required"
+			if nf.Optional {
+				from, to = to, from
+			}
+			// Making a field required breaks new readers of old data, and making it optional breaks old
+			// readers of new data.
+			compatibility := WireBreaking
+			if (nf.Optional && c.direction == Backward) || (!nf.Optional && c.direction == Forward) {
+				compatibility = SourceBreaking
+			}
+			c.add(Modified, compatibility, n, nf.Name, nfile, nf, "%s changed from %s to %s", describe(of), from, to)
+		}
+		if fmt.Sprint(of.Default) != fmt.Sprint(nf.Default) {
+			c.add(Modified, Safe, n, nf.Name, nfile, nf, "default of %s changed", describe(of))
+		}
+	}
+	for _, nf := range newer {
+		if _, ok := oldByID[nf.ID]; ok {
+			continue
+		}
+		if of := oldByName[nf.Name]; of != nil && newByID[of.ID] == nil {
+			// Reported as renumbered.
+			continue
+		}
+		// New readers require the field in old data.
+		compatibility := Safe
+		if requiredness && !nf.Optional && c.direction != Forward {
+			compatibility = WireBreaking
+		}
+		c.add(Added, compatibility, n, nf.Name, nfile, nf, "%s was added", describe(nf))
+	}
+}
+
+func fieldsByID(fields []*parser.Field) map[int]*parser.Field {
+	out := map[int]*parser.Field{}
+	for _, f := range fields {
+		out[f.ID] = f
+	}
+	return out
+}
+
+// Compare the annotations and doc comments of two versions of a node, if enabled.
+func (c *comparer) compareDetails(n *compared, member, what string, o, node interface{}) {
+	if !c.details {
+		return
+	}
+	if nodeComment(o) != nodeComment(node) {
+		c.add(Modified, Safe, n, member, n.file, node, "doc comment of %s changed", what)
+	}
+	oldAnnotations := map[string]string{}
+	for _, annotation := range nodeAnnotations(o) {
+		oldAnnotations[annotation.Name] = annotation.Value
+	}
+	newAnnotations := map[string]string{}
+	for _, annotation := range nodeAnnotations(node) {
+		newAnnotations[annotation.Name] = annotation.Value
+		value, ok := oldAnnotations[annotation.Name]
+		switch {
+		case !ok:
+			c.add(Added, Safe, n, member, n.file, node, "annotation %s = %q was added to %s", annotation.Name,
+				annotation.Value, what)
+		case value != annotation.Value:
+			c.add(Modified, Safe, n, member, n.file, node, "annotation %s of %s changed from %q to %q",
+				annotation.Name, what, value, annotation.Value)
+		}
+	}
+	for _, annotation := range nodeAnnotations(o) {
+		if _, ok := newAnnotations[annotation.Name]; !ok {
+			c.add(Removed, Safe, n, member, n.file, node, "annotation %s was removed from %s", annotation.Name, what)
+		}
+	}
+}
+
+// Return the doc comment of an AST node, or "" if it has none or the node can not have one.
+func nodeComment(node interface{}) string {
+	if !reflect.Indirect(reflect.ValueOf(node)).FieldByName("Comment").IsValid() {
+		return ""
+	}
+	return strings.Join(Comment(node), "\n")
+}
+
+// Return the annotations of an AST node, or nil if the node can not have annotations.
+func nodeAnnotations(node interface{}) []*parser.Annotation {
+	v := reflect.Indirect(reflect.ValueOf(node)).FieldByName("Annotations")
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface().([]*parser.Annotation)
+}
+
+// Compare two types, returning the compatibility of the change and whether they differ at all.
+func (c *comparer) compareTypes(ofile *parser.Thrift, o *parser.Type, nfile *parser.Thrift, n *parser.Type) (Compatibility, bool) {
+	ocanon, owire := c.canonicalType(ofile, o)
+	ncanon, nwire := c.canonicalType(nfile, n)
+	switch {
+	case owire != nwire:
+		return WireBreaking, true
+	case ocanon != ncanon:
+		return SourceBreaking, true
+	}
+	return Safe, false
+}
+
+// Return the canonical form of a type, with typedefs resolved and definitions fully qualified, and
+// its wire form, where types with identical encodings are equivalent.
+func (c *comparer) canonicalType(file *parser.Thrift, t *parser.Type) (string, string) {
+	resolved, err := ResolveType(t, file)
+	if err != nil {
+		return t.String(), t.String()
+	}
+	switch resolved.Kind {
+	case BuiltinKind:
+		if resolved.Type.Name == "binary" {
+			return "binary", "string"
+		}
+		return resolved.Type.Name, resolved.Type.Name
+	case ContainerKind:
+		vcanon, vwire := c.canonicalType(resolved.File, resolved.Type.ValueType)
+		if resolved.Type.Name == "map" {
+			kcanon, kwire := c.canonicalType(resolved.File, resolved.Type.KeyType)
+			return "map<" + kcanon + "," + vcanon + ">", "map<" + kwire + "," + vwire + ">"
+		}
+		return resolved.Type.Name + "<" + vcanon + ">", resolved.Type.Name + "<" + vwire + ">"
+	}
+	name := resolved.Type.Name
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	definitionFile := resolved.File
+	if _, file, _ := ResolveDefinition(resolved.Type.Name, resolved.File); file != nil {
+		definitionFile = file
+	}
+	canon := c.path(definitionFile) + "." + name
+	if resolved.Kind == EnumKind {
+		return canon, "i32"
+	}
+	return canon, canon
+}
+
+// Path of a file relative to its tree root, or its absolute path if it is in neither tree.
+func (c *comparer) path(file *parser.Thrift) string {
+	if path, ok := c.paths[file]; ok {
+		return path
+	}
+	return filePath(file.Filename)
+}
+
+func typeString(t *parser.Type) string {
+	if t == nil {
+		return "void"
+	}
+	return t.String()
+}
+
+func sortedNames(m map[string]bool) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedEnumValueNames(m map[string]*parser.EnumValue) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedMethodNames(m map[string]*parser.Method) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,122 @@
+package thriftlint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alecthomas/go-thrift/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	old := parseTestFiles(t, map[string]string{
+		"common.thrift": `
+typedef i64 UserId
+enum Status { ACTIVE = 1, DELETED = 2, BANNED = 3, OLD = 4 }
+struct Removed {}
+`,
+		"service.thrift": `
+include "common.thrift"
+
+struct User {
+  1: required common.UserId id
+  2: optional string name
+  3: optional common.Status status
+  4: optional string email
+  5: optional i32 age
+  6: optional string nick
+}
+
+exception NotFound {}
+
+service UserService {
+  User getUser(1: common.UserId id) throws (1: NotFound notFound)
+  void deleteUser(1: common.UserId id)
+}
+`,
+	})
+	newer := parseTestFiles(t, map[string]string{
+		"common.thrift": `
+typedef i64 UserId
+typedef i64 Id
+enum Status { ACTIVE = 1, DELETED = 5, SUSPENDED = 3, NEW = 6 }
+`,
+		"service.thrift": `
+include "common.thrift"
+
+struct User {
+  1: required common.Id id
+  2: optional string fullName
+  3: optional i32 status
+  4: required string email
+  7: optional i32 age
+  8: optional string avatar
+  9: required string country
+}
+
+exception NotFound {}
+
+service UserService {
+  User getUser(1: common.UserId id)
+}
+`,
+	})
+	changes, err := Compare("/", old, "/", newer)
+	require.NoError(t, err)
+	actual := map[string]Compatibility{}
+	for _, change := range changes {
+		actual[change.Message] = change.Compatibility
+		// Removals point at the old schema.
+		if change.Message == "common.Status.OLD (4) was removed" {
+			require.Equal(t, old["/common.thrift"], change.File)
+			require.Equal(t, old["/common.thrift"].Enums["Status"], change.Object)
+		}
+	}
+	require.Equal(t, map[string]Compatibility{
+		"typedef common.Id was added":                                                  Safe,
+		"value of common.Status.DELETED changed from 2 to 5":                           WireBreaking,
+		"common.Status.BANNED was renamed to SUSPENDED":                                SourceBreaking,
+		"common.Status.OLD (4) was removed":                                            WireBreaking,
+		"common.Status.NEW (6) was added":                                              Safe,
+		"struct common.Removed was removed":                                            SourceBreaking,
+		"field 2 \"name\" of service.User was renamed to \"fullName\"":                 SourceBreaking,
+		"type of field 3 \"status\" of service.User changed from common.Status to i32": SourceBreaking,
+		"field 4 \"email\" of service.User changed from optional to required":          WireBreaking,
+		"field 5 \"age\" of service.User was renumbered to 7":                          WireBreaking,
+		"field 6 \"nick\" of service.User was removed":                                 SourceBreaking,
+		"field 8 \"avatar\" of service.User was added":                                 Safe,
+		"field 9 \"country\" of service.User was added":                                WireBreaking,
+		"exception 1 \"notFound\" was removed from service.UserService.getUser":        WireBreaking,
+		"method service.UserService.deleteUser was removed":                            WireBreaking,
+	}, actual)
+	require.Equal(t, WireBreaking, changes.Max())
+
+	changes, err = Compare("/", old, "/", old)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(changes))
+}
+
+func TestCompareDirsOutsideTree(t *testing.T) {
+	dir, err := ioutil.TempDir("", "thriftlint-compat")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	write := func(path, content string) {
+		path = filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+	// The trees are at different depths relative to the shared include directory.
+	write("include/common.thrift", "struct User {}\n")
+	write("old/service.thrift", "include \"common.thrift\"\nstruct Request {\n  1: optional common.User user\n}\n")
+	write("v2/new/service.thrift", "include \"common.thrift\"\nstruct Request {\n  1: optional common.User user\n}\n")
+
+	changes, err := CompareDirs(filepath.Join(dir, "old"), filepath.Join(dir, "v2/new"),
+		[]string{filepath.Join(dir, "include")})
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	c := &comparer{paths: map[*parser.Thrift]string{}}
+	require.Equal(t, "/include/common", c.path(&parser.Thrift{Filename: "/include/common.thrift"}))
+}
@@ -1,46 +1,219 @@
 package thriftlint
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/alecthomas/go-thrift/parser"
 )
 
+// Kind of a Thrift definition or type.
+type Kind int
+
+// Definition and type kinds.
+const (
+	UnknownKind Kind = iota
+	// BuiltinKind is a base type such as i32 or string.
+	BuiltinKind
+	// ContainerKind is a map, list or set.
+	ContainerKind
+	StructKind
+	UnionKind
+	ExceptionKind
+	EnumKind
+	TypedefKind
+	ConstantKind
+	ServiceKind
+	EnumValueKind
+)
+
+func (k Kind) String() string {
+	switch k {
+	case BuiltinKind:
+		return "builtin"
+	case ContainerKind:
+		return "container"
+	case StructKind:
+		return "struct"
+	case UnionKind:
+		return "union"
+	case ExceptionKind:
+		return "exception"
+	case EnumKind:
+		return "enum"
+	case TypedefKind:
+		return "typedef"
+	case ConstantKind:
+		return "constant"
+	case ServiceKind:
+		return "service"
+	case EnumValueKind:
+		return "enum value"
+	}
+	return "unknown"
+}
+
 // Resolve a symbol within a file to its type.
+//
+// Symbols may be qualified by an include name, eg. "pkg.Struct". Enum values may be referenced as
+// "Enum.VALUE" or "pkg.Enum.VALUE", in which case the *parser.EnumValue is returned.
 func Resolve(symbol string, file *parser.Thrift) interface{} {
-	parts := strings.SplitN(symbol, ".", 2)
-	name := symbol
-	target := file
-	if len(parts) == 2 {
-		target = file.Imports[parts[0]]
-		if target == nil {
+	definition, _, _ := ResolveDefinition(symbol, file)
+	return definition
+}
+
+// ResolveMember resolves a symbol within a file like Resolve, additionally resolving members of the
+// form "Type.member" or "pkg.Type.member" to the *parser.Field of a struct, union or exception, or
+// the *parser.Method of a service or the services it extends.
+func ResolveMember(symbol string, file *parser.Thrift) interface{} {
+	if definition := Resolve(symbol, file); definition != nil {
+		return definition
+	}
+	dot := strings.LastIndex(symbol, ".")
+	if dot < 0 {
+		return nil
+	}
+	member := symbol[dot+1:]
+	definition, definitionFile, _ := ResolveDefinition(symbol[:dot], file)
+	seen := map[*parser.Service]bool{}
+	for definition != nil {
+		switch node := definition.(type) {
+		case *parser.Struct:
+			for _, field := range node.Fields {
+				if field.Name == member {
+					return field
+				}
+			}
 			return nil
-		}
-		name = parts[1]
-		if target == nil {
+		case *parser.Service:
+			if method, ok := node.Methods[member]; ok {
+				return method
+			}
+			if node.Extends == "" || seen[node] {
+				return nil
+			}
+			seen[node] = true
+			definition, definitionFile, _ = ResolveDefinition(node.Extends, definitionFile)
+		default:
 			return nil
 		}
 	}
+	return nil
+}
+
+// ResolveDefinition resolves a symbol within a file like Resolve, additionally returning the file
+// the definition is declared in and its kind.
+func ResolveDefinition(symbol string, file *parser.Thrift) (interface{}, *parser.Thrift, Kind) {
+	parts := strings.Split(symbol, ".")
+	switch len(parts) {
+	case 1:
+		return resolveLocal(symbol, file)
+	case 2:
+		if target := file.Imports[parts[0]]; target != nil {
+			if definition, target, kind := resolveLocal(parts[1], target); definition != nil {
+				return definition, target, kind
+			}
+		}
+		return resolveEnumValue(parts[0], parts[1], file)
+	case 3:
+		if target := file.Imports[parts[0]]; target != nil {
+			return resolveEnumValue(parts[1], parts[2], target)
+		}
+	}
+	return nil, nil, UnknownKind
+}
+
+func resolveEnumValue(enum, value string, file *parser.Thrift) (interface{}, *parser.Thrift, Kind) {
+	if e, ok := file.Enums[enum]; ok {
+		if v, ok := e.Values[value]; ok {
+			return v, file, EnumValueKind
+		}
+	}
+	return nil, nil, UnknownKind
+}
+
+// Resolve an unqualified name to a top-level definition in file.
+func resolveLocal(name string, target *parser.Thrift) (interface{}, *parser.Thrift, Kind) {
 	if t, ok := target.Constants[name]; ok {
-		return t
+		return t, target, ConstantKind
 	}
 	if t, ok := target.Enums[name]; ok {
-		return t
+		return t, target, EnumKind
 	}
 	if t, ok := target.Exceptions[name]; ok {
-		return t
+		return t, target, ExceptionKind
 	}
 	if t, ok := target.Services[name]; ok {
-		return t
+		return t, target, ServiceKind
 	}
 	if t, ok := target.Structs[name]; ok {
-		return t
+		return t, target, StructKind
 	}
 	if t, ok := target.Typedefs[name]; ok {
-		return t
+		return t, target, TypedefKind
 	}
 	if t, ok := target.Unions[name]; ok {
-		return t
+		return t, target, UnionKind
+	}
+	return nil, nil, UnknownKind
+}
+
+// ResolvedType is a type reference resolved through any typedefs to its underlying type.
+type ResolvedType struct {
+	Kind Kind
+	// Type is the underlying type reference. The KeyType and ValueType of containers must be
+	// resolved relative to File.
+	Type *parser.Type
+	// File that Type appears in.
+	File *parser.Thrift
+	// Definition that Type refers to, or nil for builtin and container types.
+	Definition interface{}
+	// Typedefs followed to reach Type, in order.
+	Typedefs []*parser.Typedef
+}
+
+// ResolveType resolves a type reference appearing in file to its underlying builtin, container,
+// struct, union, exception or enum type, following typedef chains across includes.
+//
+// If the type refers to a definition that is not a type, such as a service or constant, it is
+// returned with the corresponding Kind. An error is returned if the type, or any typedef in the
+// chain, refers to an unknown type, or if the typedefs form a cycle.
+func ResolveType(t *parser.Type, file *parser.Thrift) (*ResolvedType, error) {
+	resolved := &ResolvedType{Type: t, File: file}
+	seen := map[*parser.Typedef]bool{}
+	for {
+		switch {
+		case resolved.Type.Name == "map" || resolved.Type.Name == "list" || resolved.Type.Name == "set":
+			resolved.Kind = ContainerKind
+			return resolved, nil
+		case BuiltinThriftTypes[resolved.Type.Name] || resolved.Type.Name == "binary":
+			resolved.Kind = BuiltinKind
+			return resolved, nil
+		}
+		definition, definitionFile, kind := ResolveDefinition(resolved.Type.Name, resolved.File)
+		if definition == nil {
+			if len(resolved.Typedefs) > 0 {
+				return nil, fmt.Errorf("unknown type %q (via typedef %s)", resolved.Type.Name,
+					resolved.Typedefs[len(resolved.Typedefs)-1].Alias)
+			}
+			return nil, fmt.Errorf("unknown type %q", resolved.Type.Name)
+		}
+		typedef, ok := definition.(*parser.Typedef)
+		if !ok {
+			resolved.Kind = kind
+			resolved.Definition = definition
+			return resolved, nil
+		}
+		if seen[typedef] {
+			aliases := []string{}
+			for _, t := range resolved.Typedefs {
+				aliases = append(aliases, t.Alias)
+			}
+			return nil, fmt.Errorf("typedef cycle %s -> %s", strings.Join(aliases, " -> "), typedef.Alias)
+		}
+		seen[typedef] = true
+		resolved.Typedefs = append(resolved.Typedefs, typedef)
+		resolved.Type = typedef.Type
+		resolved.File = definitionFile
 	}
-	return nil
 }
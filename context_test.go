@@ -65,3 +65,111 @@ union Union {};
 	require.NotNil(t, actual)
 	require.Equal(t, ast.Unions["Union"], actual)
 }
+
+func TestResolveType(t *testing.T) {
+	parsed, err := parser.Parse("common.thrift", []byte(`
+typedef i64 Id
+typedef Id UserId
+enum Status { OK = 0; }
+typedef Status CommonStatus
+typedef list<Id> Ids
+typedef Loop1 Loop2
+typedef Loop2 Loop1
+`))
+	require.NoError(t, err)
+	common := parsed.(*parser.Thrift)
+	parsed, err = parser.Parse("test.thrift", []byte(`
+typedef common.UserId LocalId
+typedef common.CommonStatus LocalStatus
+typedef Missing Broken
+struct Struct {}
+service Service {}
+`))
+	require.NoError(t, err)
+	ast := parsed.(*parser.Thrift)
+	ast.Imports = map[string]*parser.Thrift{"common": common}
+
+	resolved, err := ResolveType(&parser.Type{Name: "LocalId"}, ast)
+	require.NoError(t, err)
+	require.Equal(t, BuiltinKind, resolved.Kind)
+	require.Equal(t, "i64", resolved.Type.Name)
+	require.Equal(t, common, resolved.File)
+	require.Equal(t, 3, len(resolved.Typedefs))
+
+	resolved, err = ResolveType(&parser.Type{Name: "LocalStatus"}, ast)
+	require.NoError(t, err)
+	require.Equal(t, EnumKind, resolved.Kind)
+	require.Equal(t, common.Enums["Status"], resolved.Definition)
+
+	resolved, err = ResolveType(&parser.Type{Name: "common.Ids"}, ast)
+	require.NoError(t, err)
+	require.Equal(t, ContainerKind, resolved.Kind)
+	require.Equal(t, common, resolved.File)
+
+	resolved, err = ResolveType(&parser.Type{Name: "Struct"}, ast)
+	require.NoError(t, err)
+	require.Equal(t, StructKind, resolved.Kind)
+
+	resolved, err = ResolveType(&parser.Type{Name: "Service"}, ast)
+	require.NoError(t, err)
+	require.Equal(t, ServiceKind, resolved.Kind)
+
+	_, err = ResolveType(&parser.Type{Name: "Broken"}, ast)
+	require.EqualError(t, err, `unknown type "Missing" (via typedef Broken)`)
+
+	_, err = ResolveType(&parser.Type{Name: "common.Loop1"}, ast)
+	require.EqualError(t, err, "typedef cycle Loop1 -> Loop2 -> Loop1")
+}
+
+func TestResolveValues(t *testing.T) {
+	files := parseTestFiles(t, map[string]string{
+		"common.thrift": `
+enum Status { OK = 0; FAILED = 1; }
+const i32 LIMIT = 10
+`,
+		"test.thrift": `
+include "common.thrift"
+enum Local { A = 1; }
+const i32 MAX = common.LIMIT
+`,
+	})
+	common := files["/common.thrift"]
+	ast := files["/test.thrift"]
+
+	require.Equal(t, ast.Enums["Local"].Values["A"], Resolve("Local.A", ast))
+	require.Equal(t, common.Enums["Status"].Values["FAILED"], Resolve("common.Status.FAILED", ast))
+	require.Equal(t, common.Constants["LIMIT"], Resolve("common.LIMIT", ast))
+	require.Equal(t, ast.Constants["MAX"], Resolve("MAX", ast))
+	require.Nil(t, Resolve("Local.B", ast))
+	require.Nil(t, Resolve("common.Status.UNKNOWN", ast))
+	require.Nil(t, Resolve("Status.OK", ast))
+
+	_, file, kind := ResolveDefinition("common.Status.OK", ast)
+	require.Equal(t, common, file)
+	require.Equal(t, EnumValueKind, kind)
+}
+
+func TestResolveMember(t *testing.T) {
+	files := parseTestFiles(t, map[string]string{
+		"common.thrift": `
+struct User { 1: string id; }
+service Base { void ping() }
+`,
+		"test.thrift": `
+include "common.thrift"
+exception Failed { 1: string reason; }
+service Service extends common.Base { common.User get() }
+`,
+	})
+	common := files["/common.thrift"]
+	ast := files["/test.thrift"]
+
+	require.Equal(t, ast.Exceptions["Failed"], ResolveMember("Failed", ast))
+	require.Equal(t, ast.Exceptions["Failed"].Fields[0], ResolveMember("Failed.reason", ast))
+	require.Equal(t, common.Structs["User"].Fields[0], ResolveMember("common.User.id", ast))
+	require.Equal(t, ast.Services["Service"].Methods["get"], ResolveMember("Service.get", ast))
+	require.Equal(t, common.Services["Base"].Methods["ping"], ResolveMember("Service.ping", ast))
+	require.Nil(t, ResolveMember("Service.missing", ast))
+	require.Nil(t, ResolveMember("Failed.missing", ast))
+	require.Nil(t, ResolveMember("Missing.id", ast))
+}
@@ -0,0 +1,113 @@
+package thriftlint
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"github.com/alecthomas/go-thrift/parser"
+)
+
+// symbolPattern matches a possibly qualified symbol, eg. "User" or "common.UserService.get".
+const symbolPattern = `[A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)*`
+
+// DefaultDocLinkPatterns match references to other definitions in doc comments, eg. "[User]" and
+// "See UserService.getUser". Markdown links such as "[User](http://...)" are not references.
+//
+// The first group of each pattern is the referenced symbol.
+var DefaultDocLinkPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\[(` + symbolPattern + `)\](?:[^(]|$)`),
+	regexp.MustCompile(`\bSee ((?:[a-z_][A-Za-z0-9_]*\.)?[A-Z][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)?)`),
+}
+
+// ParseDocLinkPattern compiles a regular expression matching references in doc comments, whose
+// first group is the referenced symbol.
+func ParseDocLinkPattern(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid doc link pattern %q: %s", pattern, err)
+	}
+	if re.NumSubexp() < 1 {
+		return nil, fmt.Errorf("invalid doc link pattern %q, expected a group matching the referenced symbol", pattern)
+	}
+	return re, nil
+}
+
+// DocLink is a reference to another definition in a doc comment.
+type DocLink struct {
+	Pos parser.Pos
+	// Symbol referred to, eg. "User" or "UserService.getUser".
+	Symbol string
+}
+
+// DocLinks returns the references matching patterns in the doc comment of node, in order. The first
+// group of each pattern is the referenced symbol.
+//
+// Comments are not positioned in the AST, so links are found by matching patterns against the
+// comment lines adjacent to node in source, the Source of the file as parsed. Links that can not be
+// found there, eg. because they span lines or source is nil, are positioned at node.
+func DocLinks(source *Source, node interface{}, patterns []*regexp.Regexp) []*DocLink {
+	out := []*DocLink{}
+	comment := rawComment(node)
+	if comment == "" {
+		return out
+	}
+	lines := commentLines(source, Pos(node).Line)
+	for _, pattern := range patterns {
+		// Links found in the source lines, in order, matched in turn to links in the comment.
+		found := []*DocLink{}
+		for _, line := range lines {
+			text := source.Line(line)
+			for _, match := range pattern.FindAllStringSubmatchIndex(text, -1) {
+				if match[2] >= 0 {
+					found = append(found, &DocLink{Pos: parser.Pos{Line: line, Col: match[2] + 1}, Symbol: text[match[2]:match[3]]})
+				}
+			}
+		}
+		for _, match := range pattern.FindAllStringSubmatch(comment, -1) {
+			link := &DocLink{Pos: Pos(node), Symbol: match[1]}
+			for i, candidate := range found {
+				if candidate.Symbol == link.Symbol {
+					link.Pos = candidate.Pos
+					found = found[i+1:]
+					break
+				}
+			}
+			out = append(out, link)
+		}
+	}
+	return out
+}
+
+// The unformatted doc comment of a node, or "" if the node type does not have one.
+func rawComment(node interface{}) string {
+	rv := reflect.Indirect(reflect.ValueOf(node))
+	if rv.Kind() != reflect.Struct {
+		return ""
+	}
+	if f := rv.FieldByName("Comment"); f.IsValid() && f.Kind() == reflect.String {
+		return f.String()
+	}
+	return ""
+}
+
+var commentLineRegex = regexp.MustCompile(`^\s*(//|#|/\*|\*)|\*/\s*$`)
+
+// Line numbers of the block of comment lines adjacent to line, including line itself if it is a
+// comment.
+func commentLines(source *Source, line int) []int {
+	isComment := func(n int) bool { return n > 0 && commentLineRegex.MatchString(source.Line(n)) }
+	start := line - 1
+	for isComment(start) {
+		start--
+	}
+	end := line
+	for isComment(end) {
+		end++
+	}
+	out := []int{}
+	for n := start + 1; n < end; n++ {
+		out = append(out, n)
+	}
+	return out
+}
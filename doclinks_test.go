@@ -0,0 +1,59 @@
+package thriftlint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/alecthomas/go-thrift/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocLinks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "thriftlint-doclinks")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "user.thrift")
+	content := `// User is a user, not a [Profile]. See the [Docs](http://example.com).
+// The [Profile] of the user, or {@link Account}.
+// See UserService.get
+struct User {
+  /* The ID, see
+   * also [Id]. */
+  1: string id
+}
+`
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	project, err := ParseProject(nil, []string{path}, nil)
+	require.NoError(t, err)
+	file := project.Files[path]
+	source := project.Source(file)
+
+	user := file.Structs["User"]
+	require.Equal(t, []*DocLink{
+		{Pos: parser.Pos{Line: 1, Col: 27}, Symbol: "Profile"},
+		{Pos: parser.Pos{Line: 2, Col: 9}, Symbol: "Profile"},
+		{Pos: parser.Pos{Line: 3, Col: 8}, Symbol: "UserService.get"},
+	}, DocLinks(source, user, DefaultDocLinkPatterns))
+	require.Equal(t, []*DocLink{
+		{Pos: parser.Pos{Line: 6, Col: 12}, Symbol: "Id"},
+	}, DocLinks(source, user.Fields[0], DefaultDocLinkPatterns))
+	require.Equal(t, []*DocLink{}, DocLinks(source, &parser.Typedef{}, DefaultDocLinkPatterns))
+
+	// Without source, links are positioned at the node.
+	require.Equal(t, []*DocLink{
+		{Pos: user.Fields[0].Pos, Symbol: "Id"},
+	}, DocLinks(nil, user.Fields[0], DefaultDocLinkPatterns))
+
+	pattern, err := ParseDocLinkPattern(`\{@link\s+([A-Za-z.]+)\s*\}`)
+	require.NoError(t, err)
+	require.Equal(t, []*DocLink{
+		{Pos: parser.Pos{Line: 2, Col: 41}, Symbol: "Account"},
+	}, DocLinks(source, user, []*regexp.Regexp{pattern}))
+	_, err = ParseDocLinkPattern(`[A-Z]+`)
+	require.Error(t, err)
+	_, err = ParseDocLinkPattern(`(`)
+	require.Error(t, err)
+}
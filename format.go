@@ -0,0 +1,87 @@
+package thriftlint
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/alecthomas/go-thrift/parser"
+)
+
+// TemplateFuncs are the helper functions available to message templates.
+var TemplateFuncs = template.FuncMap{
+	"UpperCamelCase": UpperCamelCase,
+	"LowerCamelCase": LowerCamelCase,
+	"LowerSnakeCase": LowerSnakeCase,
+	"UpperSnakeCase": UpperSnakeCase,
+	"SplitSymbol":    SplitSymbol,
+	"DotSuffix":      DotSuffix,
+	"IsInitialism":   IsInitialism,
+}
+
+// TemplateMessage is the data model passed to message templates.
+//
+// For example, an errorformat compatible template might be:
+//
+//	{{.File}}:{{.Line}}:{{.Col}}: {{.Severity}}: {{.Message}} ({{.Checker}})
+type TemplateMessage struct {
+	// File is the filename of the Thrift file that resulted in the message.
+	File string
+	// Thrift is the AST of the file that resulted in the message.
+	Thrift *parser.Thrift
+	// Pos of Object, and its constituent Line and Col.
+	Pos  parser.Pos
+	Line int
+	Col  int
+	// Checker is the ID of the Check that generated the message.
+	Checker  string
+	Severity Severity
+	// Object is the AST node the message applies to.
+	Object  interface{}
+	Message string
+	// Path is the dotted symbol path of the node, eg. "UserService.getUser".
+	Path string
+}
+
+// NewTemplateMessage creates the template data model for a Message.
+func NewTemplateMessage(msg *Message) *TemplateMessage {
+	pos := Pos(msg.Object)
+	out := &TemplateMessage{
+		Thrift:   msg.File,
+		Pos:      pos,
+		Line:     pos.Line,
+		Col:      pos.Col,
+		Checker:  msg.Checker,
+		Severity: msg.Severity,
+		Object:   msg.Object,
+		Message:  msg.Message,
+		Path:     msg.Path,
+	}
+	if msg.File != nil {
+		out.File = msg.File.Filename
+	}
+	return out
+}
+
+// MessageTemplate formats Messages using a text/template.
+type MessageTemplate struct {
+	template *template.Template
+}
+
+// NewMessageTemplate parses a message template. See TemplateMessage for the data model, and
+// TemplateFuncs for the available helper functions.
+func NewMessageTemplate(text string) (*MessageTemplate, error) {
+	t, err := template.New("message").Funcs(TemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &MessageTemplate{template: t}, nil
+}
+
+// Execute the template for msg, writing the result followed by a newline to w.
+func (m *MessageTemplate) Execute(w io.Writer, msg *Message) error {
+	if err := m.template.Execute(w, NewTemplateMessage(msg)); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
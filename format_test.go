@@ -0,0 +1,38 @@
+package thriftlint
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/alecthomas/go-thrift/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageTemplate(t *testing.T) {
+	tmpl, err := NewMessageTemplate(`{{.File}}:{{.Line}}:{{.Col}}: {{.Severity}}: {{.Message}} [{{.Path}}] {{UpperSnakeCase .Checker}}`)
+	require.NoError(t, err)
+	msg := &Message{
+		File:     &parser.Thrift{Filename: "test.thrift"},
+		Checker:  "order",
+		Severity: Error,
+		Object:   &parser.Field{Pos: parser.Pos{Line: 3, Col: 5}},
+		Message:  "out of order",
+		Path:     "Struct.field",
+	}
+	w := &bytes.Buffer{}
+	err = tmpl.Execute(w, msg)
+	require.NoError(t, err)
+	require.Equal(t, "test.thrift:3:5: error: out of order [Struct.field] ORDER\n", w.String())
+}
+
+func TestSymbolPath(t *testing.T) {
+	ancestors := []interface{}{
+		map[string]*parser.Thrift{},
+		&parser.Thrift{},
+		&parser.Service{Name: "Service"},
+		&parser.Method{Name: "method"},
+		&parser.Field{Name: "arg"},
+		&parser.Type{Name: "string"},
+	}
+	require.Equal(t, "Service.method.arg", symbolPath(ancestors))
+}
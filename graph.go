@@ -0,0 +1,219 @@
+package thriftlint
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/alecthomas/go-thrift/parser"
+)
+
+// Graph is a directed dependency graph, eg. of includes between files.
+type Graph struct {
+	// Nodes in sorted order.
+	Nodes []string `json:"nodes"`
+	// Edges ordered by From then To.
+	Edges []*GraphEdge `json:"edges"`
+}
+
+// GraphEdge is a dependency of From on To.
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// IncludeGraph builds the graph of includes between files returned by Parse. Nodes are the
+// filenames of the files.
+func IncludeGraph(files map[string]*parser.Thrift) *Graph {
+	nodes := map[string]bool{}
+	edges := map[GraphEdge]bool{}
+	for _, file := range files {
+		nodes[file.Filename] = true
+		for _, path := range file.Includes {
+			nodes[path] = true
+			edges[GraphEdge{From: file.Filename, To: path}] = true
+		}
+	}
+	return newGraph(nodes, edges)
+}
+
+// TypeGraph builds the graph of references between the definitions in an Index. Nodes are the
+// qualified names of the definitions. References from a definition to itself are omitted.
+func TypeGraph(index *Index) *Graph {
+	nodes := map[string]bool{}
+	edges := map[GraphEdge]bool{}
+	for _, definition := range index.Definitions {
+		nodes[definition.QualifiedName()] = true
+		for _, ref := range index.References(definition) {
+			if ref.From != definition {
+				edges[GraphEdge{From: ref.From.QualifiedName(), To: definition.QualifiedName()}] = true
+			}
+		}
+	}
+	return newGraph(nodes, edges)
+}
+
+func newGraph(nodes map[string]bool, edges map[GraphEdge]bool) *Graph {
+	graph := &Graph{Nodes: []string{}, Edges: []*GraphEdge{}}
+	for node := range nodes {
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	sort.Strings(graph.Nodes)
+	for edge := range edges {
+		edge := edge
+		graph.Edges = append(graph.Edges, &edge)
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		a, b := graph.Edges[i], graph.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+	return graph
+}
+
+// WriteDot writes the graph in Graphviz DOT format.
+func (g *Graph) WriteDot(w io.Writer, name string) error {
+	if _, err := fmt.Fprintf(w, "digraph %q {\n", name); err != nil {
+		return err
+	}
+	for _, node := range g.Nodes {
+		if _, err := fmt.Fprintf(w, "  %q;\n", node); err != nil {
+			return err
+		}
+	}
+	for _, edge := range g.Edges {
+		if _, err := fmt.Fprintf(w, "  %q -> %q;\n", edge.From, edge.To); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "}\n")
+	return err
+}
+
+// Cycles returns cycles through every node that depends on itself, as paths that start and end
+// with the same node, eg. ["a", "b", "a"].
+//
+// For each group of nodes that depend on each other, the shortest cycle through the lowest sorted
+// node is returned, followed by the shortest cycle through each node not yet in a cycle. Each
+// cycle starts at the node it was found for.
+func (g *Graph) Cycles() [][]string {
+	successors := map[string][]string{}
+	for _, edge := range g.Edges {
+		successors[edge.From] = append(successors[edge.From], edge.To)
+	}
+	cycles := [][]string{}
+	for _, component := range stronglyConnectedComponents(g.Nodes, successors) {
+		members := map[string]bool{}
+		for _, node := range component {
+			members[node] = true
+		}
+		if len(component) == 1 && !contains(successors[component[0]], component[0]) {
+			continue
+		}
+		covered := map[string]bool{}
+		for _, start := range component {
+			if covered[start] {
+				continue
+			}
+			cycle := shortestCycle(start, successors, members)
+			for _, node := range cycle {
+				covered[node] = true
+			}
+			cycles = append(cycles, cycle)
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+	return cycles
+}
+
+// Tarjan's algorithm. Each component is returned sorted.
+func stronglyConnectedComponents(nodes []string, successors map[string][]string) [][]string {
+	index := 0
+	indices := map[string]int{}
+	lowlinks := map[string]int{}
+	onStack := map[string]bool{}
+	stack := []string{}
+	components := [][]string{}
+	var connect func(node string)
+	connect = func(node string) {
+		indices[node] = index
+		lowlinks[node] = index
+		index++
+		stack = append(stack, node)
+		onStack[node] = true
+		for _, next := range successors[node] {
+			if _, ok := indices[next]; !ok {
+				connect(next)
+				if lowlinks[next] < lowlinks[node] {
+					lowlinks[node] = lowlinks[next]
+				}
+			} else if onStack[next] && indices[next] < lowlinks[node] {
+				lowlinks[node] = indices[next]
+			}
+		}
+		if lowlinks[node] != indices[node] {
+			return
+		}
+		component := []string{}
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == node {
+				break
+			}
+		}
+		sort.Strings(component)
+		components = append(components, component)
+	}
+	for _, node := range nodes {
+		if _, ok := indices[node]; !ok {
+			connect(node)
+		}
+	}
+	return components
+}
+
+// Find the shortest path from start back to itself, through members only.
+func shortestCycle(start string, successors map[string][]string, members map[string]bool) []string {
+	previous := map[string]string{}
+	queue := []string{start}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, next := range successors[node] {
+			if !members[next] {
+				continue
+			}
+			if next == start {
+				path := []string{start}
+				for n := node; n != start; n = previous[n] {
+					path = append(path, n)
+				}
+				path = append(path, start)
+				// Reverse into forward order.
+				for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+				return path
+			}
+			if _, ok := previous[next]; !ok {
+				previous[next] = node
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package thriftlint
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/alecthomas/go-thrift/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncludeGraphCycles(t *testing.T) {
+	file := func(name string, includes ...string) *parser.Thrift {
+		file := &parser.Thrift{Filename: "/" + name, Includes: map[string]string{}}
+		for _, include := range includes {
+			file.Includes[include] = "/" + include
+		}
+		return file
+	}
+	files := map[string]*parser.Thrift{}
+	for _, f := range []*parser.Thrift{
+		file("a", "b"),
+		file("b", "c"),
+		file("c", "a", "d"),
+		file("d"),
+		file("e", "e"),
+		file("f", "a"),
+		file("g", "h", "i"),
+		file("h", "g"),
+		file("i", "g"),
+	} {
+		files[f.Filename] = f
+	}
+	graph := IncludeGraph(files)
+	require.Equal(t, []string{"/a", "/b", "/c", "/d", "/e", "/f", "/g", "/h", "/i"}, graph.Nodes)
+	require.Equal(t, &GraphEdge{From: "/c", To: "/a"}, graph.Edges[2])
+	require.Equal(t, [][]string{
+		{"/a", "/b", "/c", "/a"},
+		{"/e", "/e"},
+		{"/g", "/h", "/g"},
+		{"/i", "/g", "/i"},
+	}, graph.Cycles())
+
+	w := &bytes.Buffer{}
+	require.NoError(t, IncludeGraph(map[string]*parser.Thrift{"/a": file("a", "b")}).WriteDot(w, "includes"))
+	require.Equal(t, "digraph \"includes\" {\n  \"/a\";\n  \"/b\";\n  \"/a\" -> \"/b\";\n}\n", w.String())
+}
+
+func TestTypeGraph(t *testing.T) {
+	files := parseTestFiles(t, map[string]string{
+		"common.thrift": `
+struct User {}
+struct Node {
+  1: optional list<Node> children
+}
+`,
+		"service.thrift": `
+include "common.thrift"
+
+service UserService {
+  common.User getUser(1: common.User user)
+}
+`,
+	})
+	graph := TypeGraph(NewIndex(files))
+	require.Equal(t, []string{"common.Node", "common.User", "service.UserService"}, graph.Nodes)
+	require.Equal(t, []*GraphEdge{{From: "service.UserService", To: "common.User"}}, graph.Edges)
+}
@@ -0,0 +1,56 @@
+package thriftlint
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/go-thrift/parser"
+)
+
+var includeRegex = regexp.MustCompile(`(?m)^[ \t]*include[ \t]+["']([^"']+)["']`)
+
+// Include is an include statement in a Thrift file.
+type Include struct {
+	Pos parser.Pos
+	// Name the included file is referred to by, ie. its base name without extension.
+	Name string
+	// Path as written in the include statement.
+	Path string
+}
+
+// Includes returns the include statements of a file in source order, ignoring any in comments.
+//
+// The AST does not record include statements, so they are found in source, the Source of the file
+// as parsed. If source is nil, the includes are derived from the AST, ordered by name and without
+// positions.
+func Includes(file *parser.Thrift, source *Source) []*Include {
+	if source == nil {
+		out := []*Include{}
+		for name, path := range file.Includes {
+			out = append(out, &Include{Name: name, Path: path})
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+		return out
+	}
+	out := []*Include{}
+	code := source.Code()
+	for _, match := range includeRegex.FindAllSubmatchIndex(code, -1) {
+		path := string(code[match[2]:match[3]])
+		name := filepath.Base(path)
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+		out = append(out, &Include{Pos: sourcePos(code, match[0]), Name: name, Path: path})
+	}
+	return out
+}
+
+// Convert a byte offset to the position of the first non-blank character at or after it.
+func sourcePos(source []byte, offset int) parser.Pos {
+	for offset < len(source) && (source[offset] == ' ' || source[offset] == '\t') {
+		offset++
+	}
+	line := 1 + strings.Count(string(source[:offset]), "\n")
+	col := offset - strings.LastIndex(string(source[:offset]), "\n")
+	return parser.Pos{Line: line, Col: col}
+}
@@ -0,0 +1,35 @@
+package thriftlint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alecthomas/go-thrift/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncludes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "thriftlint-includes")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "common.thrift"), []byte("struct User {}\n"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "sub", "other.thrift"), []byte("struct Other {}\n"), 0644))
+	main := filepath.Join(dir, "main.thrift")
+	source := "// include \"commented.thrift\"\ninclude \"common.thrift\"\n/*\n  include 'sub/other.thrift'\n*/\n  include 'sub/other.thrift'\n"
+	require.NoError(t, ioutil.WriteFile(main, []byte(source), 0644))
+
+	project, err := ParseProject([]string{dir}, []string{main}, nil)
+	require.NoError(t, err)
+	file := project.Files[main]
+	require.Equal(t, []*Include{
+		{Pos: parser.Pos{Line: 2, Col: 1}, Name: "common", Path: "common.thrift"},
+		{Pos: parser.Pos{Line: 6, Col: 3}, Name: "other", Path: "sub/other.thrift"},
+	}, Includes(file, project.Source(file)))
+
+	// Without source, includes are derived from the AST.
+	unparsed := &parser.Thrift{Filename: "/missing.thrift", Includes: map[string]string{"b": "/b.thrift", "a": "/a.thrift"}}
+	require.Equal(t, []*Include{{Name: "a", Path: "/a.thrift"}, {Name: "b", Path: "/b.thrift"}}, Includes(unparsed, nil))
+}
@@ -0,0 +1,223 @@
+package thriftlint
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/go-thrift/parser"
+)
+
+// Definition is a top-level named definition in a Thrift file.
+type Definition struct {
+	Name string
+	Kind Kind
+	// File the definition is declared in.
+	File *parser.Thrift
+	// Node is the AST node of the definition, eg. *parser.Struct.
+	Node interface{}
+	Pos  parser.Pos
+}
+
+// QualifiedName of the definition, as it would be referenced from a file including its file,
+// eg. "common.User".
+func (d *Definition) QualifiedName() string {
+	return IncludeName(d.File) + "." + d.Name
+}
+
+// Reference to a Definition.
+type Reference struct {
+	// File containing the reference.
+	File *parser.Thrift
+	// Node containing the reference. This is a *parser.Type for type references, the
+	// *parser.Constant or *parser.Field whose value or default refers to a constant or enum value,
+	// or the *parser.Service for "extends" references.
+	Node interface{}
+	// From is the definition containing the reference, eg. the struct of a referencing field.
+	From *Definition
+	Pos  parser.Pos
+}
+
+// Index of definitions across a set of parsed files, and the references to them.
+type Index struct {
+	// Definitions in the indexed files, ordered by filename and position.
+	Definitions []*Definition
+	definitions map[interface{}]*Definition
+	references  map[*Definition][]*Reference
+}
+
+// IncludeName returns the name a file is referred to by when included, ie. its base name without
+// extension.
+func IncludeName(file *parser.Thrift) string {
+	name := filepath.Base(file.Filename)
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// NewIndex builds an index from the output of Parse.
+//
+// References are resolved with the same semantics as Resolve.
+func NewIndex(files map[string]*parser.Thrift) *Index {
+	index := &Index{
+		definitions: map[interface{}]*Definition{},
+		references:  map[*Definition][]*Reference{},
+	}
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		index.addDefinitions(files[path])
+	}
+	sort.SliceStable(index.Definitions, func(i, j int) bool {
+		a, b := index.Definitions[i], index.Definitions[j]
+		if a.File.Filename != b.File.Filename {
+			return a.File.Filename < b.File.Filename
+		}
+		if a.Pos.Line != b.Pos.Line {
+			return a.Pos.Line < b.Pos.Line
+		}
+		return a.Pos.Col < b.Pos.Col
+	})
+	for _, definition := range index.Definitions {
+		index.addReferences(definition)
+	}
+	return index
+}
+
+func (i *Index) addDefinitions(file *parser.Thrift) {
+	add := func(name string, kind Kind, node interface{}) {
+		definition := &Definition{Name: name, Kind: kind, File: file, Node: node, Pos: Pos(node)}
+		i.Definitions = append(i.Definitions, definition)
+		i.definitions[node] = definition
+	}
+	for name, node := range file.Typedefs {
+		add(name, TypedefKind, node)
+	}
+	for name, node := range file.Constants {
+		add(name, ConstantKind, node)
+	}
+	for name, node := range file.Enums {
+		add(name, EnumKind, node)
+	}
+	for name, node := range file.Structs {
+		add(name, StructKind, node)
+	}
+	for name, node := range file.Unions {
+		add(name, UnionKind, node)
+	}
+	for name, node := range file.Exceptions {
+		add(name, ExceptionKind, node)
+	}
+	for name, node := range file.Services {
+		add(name, ServiceKind, node)
+	}
+}
+
+func (i *Index) addReferences(from *Definition) {
+	switch node := from.Node.(type) {
+	case *parser.Typedef:
+		i.addTypeReferences(from, node.Type)
+	case *parser.Constant:
+		i.addTypeReferences(from, node.Type)
+		i.addValueReferences(from, node, node.Value)
+	case *parser.Struct:
+		i.addFieldReferences(from, node.Fields)
+	case *parser.Service:
+		if node.Extends != "" {
+			i.addReference(from, node, node.Extends, node.Pos)
+		}
+		methods := make([]*parser.Method, 0, len(node.Methods))
+		for _, method := range node.Methods {
+			methods = append(methods, method)
+		}
+		sort.Slice(methods, func(a, b int) bool {
+			if methods[a].Pos.Line != methods[b].Pos.Line {
+				return methods[a].Pos.Line < methods[b].Pos.Line
+			}
+			return methods[a].Pos.Col < methods[b].Pos.Col
+		})
+		for _, method := range methods {
+			if method.ReturnType != nil {
+				i.addTypeReferences(from, method.ReturnType)
+			}
+			i.addFieldReferences(from, method.Arguments)
+			i.addFieldReferences(from, method.Exceptions)
+		}
+	}
+}
+
+func (i *Index) addFieldReferences(from *Definition, fields []*parser.Field) {
+	for _, field := range fields {
+		i.addTypeReferences(from, field.Type)
+		i.addValueReferences(from, field, field.Default)
+	}
+}
+
+func (i *Index) addTypeReferences(from *Definition, t *parser.Type) {
+	if t == nil {
+		return
+	}
+	if !BuiltinThriftTypes[t.Name] && !BuiltinThriftCollections[t.Name] {
+		i.addReference(from, t, t.Name, t.Pos)
+	}
+	i.addTypeReferences(from, t.KeyType)
+	i.addTypeReferences(from, t.ValueType)
+}
+
+// Add references from identifiers in constant values, recursing into lists and maps.
+func (i *Index) addValueReferences(from *Definition, node interface{}, value interface{}) {
+	switch value := value.(type) {
+	case parser.Identifier:
+		i.addReference(from, node, string(value), Pos(node))
+	case []interface{}:
+		for _, v := range value {
+			i.addValueReferences(from, node, v)
+		}
+	case []parser.KeyValue:
+		for _, kv := range value {
+			i.addValueReferences(from, node, kv.Key)
+			i.addValueReferences(from, node, kv.Value)
+		}
+	}
+}
+
+func (i *Index) addReference(from *Definition, node interface{}, symbol string, pos parser.Pos) {
+	resolved := Resolve(symbol, from.File)
+	// References to enum values are references to their enum.
+	if _, ok := resolved.(*parser.EnumValue); ok {
+		resolved = Resolve(symbol[:strings.LastIndex(symbol, ".")], from.File)
+	}
+	target := i.definitions[resolved]
+	if target == nil {
+		return
+	}
+	i.references[target] = append(i.references[target], &Reference{
+		File: from.File,
+		Node: node,
+		From: from,
+		Pos:  pos,
+	})
+}
+
+// Definition returns the Definition for an AST node, or nil if the node is not a definition.
+func (i *Index) Definition(node interface{}) *Definition {
+	return i.definitions[node]
+}
+
+// Find definitions matching symbol, which may be an unqualified name such as "User" or a
+// qualified name such as "common.User".
+func (i *Index) Find(symbol string) []*Definition {
+	out := []*Definition{}
+	for _, definition := range i.Definitions {
+		if definition.Name == symbol || definition.QualifiedName() == symbol {
+			out = append(out, definition)
+		}
+	}
+	return out
+}
+
+// References to a definition, in the order they were indexed.
+func (i *Index) References(definition *Definition) []*Reference {
+	return i.references[definition]
+}
@@ -0,0 +1,101 @@
+package thriftlint
+
+import (
+	"testing"
+
+	"github.com/alecthomas/go-thrift/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func parseTestFiles(t *testing.T, sources map[string]string) map[string]*parser.Thrift {
+	files := map[string]*parser.Thrift{}
+	for name, source := range sources {
+		parsed, err := parser.Parse(name, []byte(source))
+		require.NoError(t, err)
+		file := parsed.(*parser.Thrift)
+		file.Filename = "/" + name
+		files[file.Filename] = file
+	}
+	for _, file := range files {
+		file.Imports = map[string]*parser.Thrift{}
+		for name := range file.Includes {
+			file.Imports[name] = files["/"+name+".thrift"]
+		}
+	}
+	return files
+}
+
+func TestIndex(t *testing.T) {
+	files := parseTestFiles(t, map[string]string{
+		"common.thrift": `
+struct User {}
+typedef i64 UserId
+const i32 LIMIT = 10
+service Base {}
+`,
+		"service.thrift": `
+include "common.thrift"
+
+const i32 MAX = common.LIMIT
+typedef common.User Person
+
+struct Request {
+  1: optional common.UserId id
+  2: optional list<common.User> users
+  3: optional i32 limit = common.LIMIT
+}
+
+service UserService extends common.Base {
+  common.User getUser(1: Request request)
+}
+`,
+	})
+	index := NewIndex(files)
+
+	users := index.Find("common.User")
+	require.Equal(t, 1, len(users))
+	user := users[0]
+	require.Equal(t, index.Find("User"), users)
+	require.Equal(t, StructKind, user.Kind)
+	require.Equal(t, files["/common.thrift"].Structs["User"], user.Node)
+	require.Equal(t, user, index.Definition(user.Node))
+
+	refs := index.References(user)
+	require.Equal(t, 3, len(refs))
+	froms := []string{}
+	for _, ref := range refs {
+		require.Equal(t, files["/service.thrift"], ref.File)
+		froms = append(froms, ref.From.Name)
+	}
+	require.Equal(t, []string{"Person", "Request", "UserService"}, froms)
+
+	limit := index.Find("LIMIT")[0]
+	refs = index.References(limit)
+	require.Equal(t, 2, len(refs))
+	require.Equal(t, files["/service.thrift"].Constants["MAX"], refs[0].Node)
+	require.Equal(t, files["/service.thrift"].Structs["Request"].Fields[2], refs[1].Node)
+
+	base := index.Find("Base")[0]
+	refs = index.References(base)
+	require.Equal(t, 1, len(refs))
+	require.Equal(t, files["/service.thrift"].Services["UserService"], refs[0].Node)
+
+	require.Equal(t, 1, len(index.References(index.Find("UserId")[0])))
+	require.Equal(t, 0, len(index.Find("Missing")))
+}
+
+func TestIndexMethodReferencesOrder(t *testing.T) {
+	files := parseTestFiles(t, map[string]string{
+		"test.thrift": `
+struct A {}
+service Test { A zeta(), A beta(), A alpha() }
+`,
+	})
+	index := NewIndex(files)
+	refs := index.References(index.Find("A")[0])
+	require.Equal(t, 3, len(refs))
+	// Methods on the same line are visited in column order.
+	for i := 1; i < len(refs); i++ {
+		require.True(t, refs[i-1].Pos.Col < refs[i].Pos.Col)
+	}
+}
@@ -20,6 +20,7 @@ type logger interface {
 type Linter struct {
 	checkers    Checks
 	includeDirs []string
+	overlay     map[string][]byte
 	log         logger
 }
 
@@ -30,6 +31,12 @@ func WithIncludeDirs(dirs ...string) Option {
 	return func(l *Linter) { l.includeDirs = dirs }
 }
 
+// WithOverlay is an Option that lints the given in-memory content, keyed by absolute path, in
+// place of the corresponding files on disk.
+func WithOverlay(files map[string][]byte) Option {
+	return func(l *Linter) { l.overlay = files }
+}
+
 // WithLogger is an Option that sets the logger object used by the linter.
 func WithLogger(logger logger) Option {
 	return func(l *Linter) { l.log = logger }
@@ -62,17 +69,17 @@ func New(checks []Check, options ...Option) (*Linter, error) {
 // Lint the given files.
 func (l *Linter) Lint(sources []string) (Messages, error) {
 	l.log.Printf("Parsing %d files", len(sources))
-	files, err := Parse(l.includeDirs, sources)
+	project, err := ParseProject(l.includeDirs, sources, l.overlay)
 	if err != nil {
 		return nil, err
 	}
 	messages := Messages{}
-	for _, file := range files {
+	for _, file := range project.Files {
 		l.log.Printf("Linting %s", file.Filename)
 		v := reflect.ValueOf(file)
 		enabledChecks := l.checkers.CloneAndDisable()
-		// Seed the "ancestors" with imports.
-		ancestors := []interface{}{file.Imports}
+		// Seed the "ancestors" with the project and imports.
+		ancestors := []interface{}{project, file.Imports}
 		messages = append(messages, l.walk(file, ancestors, v, enabledChecks)...)
 	}
 	return messages, nil
@@ -105,11 +112,13 @@ func (l *Linter) walk(file *parser.Thrift, ancestors []interface{}, v reflect.Va
 		}
 
 		ancestors = append(ancestors, originalNode.Interface())
+		path := symbolPath(ancestors)
 		for _, checker := range enabledChecks {
 			id := checker.ID()
 			for _, msg := range callChecker(checker.Checker(), ancestors) {
 				msg.File = file
 				msg.Checker = id
+				msg.Path = path
 				messages = append(messages, msg)
 			}
 		}
@@ -135,6 +144,32 @@ func (l *Linter) walk(file *parser.Thrift, ancestors []interface{}, v reflect.Va
 	return
 }
 
+// Build the dotted symbol path of the named definitions in ancestors, eg. "Service.method".
+func symbolPath(ancestors []interface{}) string {
+	parts := []string{}
+	for _, ancestor := range ancestors {
+		switch node := ancestor.(type) {
+		case *parser.Service:
+			parts = append(parts, node.Name)
+		case *parser.Method:
+			parts = append(parts, node.Name)
+		case *parser.Struct:
+			parts = append(parts, node.Name)
+		case *parser.Field:
+			parts = append(parts, node.Name)
+		case *parser.Enum:
+			parts = append(parts, node.Name)
+		case *parser.EnumValue:
+			parts = append(parts, node.Name)
+		case *parser.Constant:
+			parts = append(parts, node.Name)
+		case *parser.Typedef:
+			parts = append(parts, node.Alias)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
 // Apparently it's non-trivial to get the type of the empty interface...
 var emptyInterfaceValue interface{}
 var emptyInterfaceType = reflect.TypeOf(&emptyInterfaceValue).Elem()
@@ -156,6 +191,15 @@ var emptyInterfaceType = reflect.TypeOf(&emptyInterfaceValue).Elem()
 // 		f(*parser.Struct)
 // 		f(*parser.Field, *parser.Struct)
 //
+// The last argument may also be interface{}, in which case it matches any node:
+//
+// 		f(*parser.Thrift, interface{})
+//
+// Each ancestor is passed to at most one argument, so an interface{} argument before the last
+// receives an earlier ancestor rather than the node itself, eg. the parent of a field:
+//
+// 		f(*parser.Thrift, interface{}, *parser.Field)
+//
 func callChecker(checker interface{}, ancestors []interface{}) Messages {
 	l := reflect.TypeOf(checker)
 	if l.Kind() != reflect.Func {
@@ -182,8 +226,9 @@ func callChecker(checker interface{}, ancestors []interface{}) Messages {
 		)
 
 	default:
-		// Ensure last argument matches last ancestor.
-		if reflect.TypeOf(ancestors[len(ancestors)-1]) != l.In(l.NumIn()-1) {
+		// Ensure last argument matches last ancestor, or is interface{} and matches any node.
+		last := l.In(l.NumIn() - 1)
+		if last != emptyInterfaceType && reflect.TypeOf(ancestors[len(ancestors)-1]) != last {
 			return nil
 		}
 
@@ -198,6 +243,9 @@ func callChecker(checker interface{}, ancestors []interface{}) Messages {
 				arg := ancestorsValues[ancestorIndex]
 				if arg.Type().ConvertibleTo(l.In(parameterIndex)) {
 					args = append(args, arg)
+					// Otherwise an interface{} parameter would receive the ancestor matched by the
+					// parameter after it.
+					ancestorIndex--
 					break
 				}
 				ancestorIndex--
@@ -24,6 +24,8 @@ func TestCallChecker(t *testing.T) {
 		func(*parser.Field) Messages { return Messages{} },
 		func(self interface{}) Messages { return Messages{} },
 		func(parent, self interface{}) Messages { return Messages{} },
+		func(*parser.Thrift, interface{}) Messages { return Messages{} },
+		func(*parser.Struct, interface{}) Messages { return Messages{} },
 	}
 	ancestors := []interface{}{&parser.Thrift{}, &parser.Struct{}, &parser.Field{}}
 	for _, okf := range okfuncs {
@@ -35,9 +37,94 @@ func TestCallChecker(t *testing.T) {
 		func(*parser.Thrift) Messages { return Messages{} },
 		func(*parser.Struct) Messages { return Messages{} },
 		func(*parser.Field, *parser.Struct) Messages { return Messages{} },
+		func(*parser.Service, interface{}) Messages { return Messages{} },
 	}
 	for _, badf := range badfuncs {
 		out := callChecker(badf, ancestors)
 		require.Nil(t, out)
 	}
+
+	var parent interface{}
+	callChecker(func(file *parser.Thrift, p interface{}, field *parser.Field) Messages {
+		parent = p
+		return Messages{}
+	}, ancestors)
+	require.Equal(t, ancestors[1], parent)
+}
+
+func TestCallCheckerArguments(t *testing.T) {
+	project := NewProject(map[string]*parser.Thrift{})
+	imports := map[string]*parser.Thrift{}
+	file := &parser.Thrift{}
+	s := &parser.Struct{}
+	field := &parser.Field{}
+	ancestors := []interface{}{project, imports, file, s, field}
+
+	// Signatures used by the checks package, with the arguments each should receive.
+	tests := []struct {
+		checker  func(args *[]interface{}) interface{}
+		expected []interface{}
+	}{
+		{func(args *[]interface{}) interface{} {
+			return func(p *Project, f *parser.Thrift, self interface{}) Messages {
+				*args = []interface{}{p, f, self}
+				return Messages{}
+			}
+		}, []interface{}{project, file, field}},
+		{func(args *[]interface{}) interface{} {
+			return func(p *Project, f *parser.Thrift) Messages {
+				*args = []interface{}{p, f}
+				return Messages{}
+			}
+		}, nil},
+		{func(args *[]interface{}) interface{} {
+			return func(p *Project, st *parser.Struct) Messages {
+				*args = []interface{}{p, st}
+				return Messages{}
+			}
+		}, nil},
+		{func(args *[]interface{}) interface{} {
+			return func(f *parser.Thrift, parent interface{}, fd *parser.Field) Messages {
+				*args = []interface{}{f, parent, fd}
+				return Messages{}
+			}
+		}, []interface{}{file, s, field}},
+		{func(args *[]interface{}) interface{} {
+			return func(f *parser.Thrift, st *parser.Struct, fd *parser.Field) Messages {
+				*args = []interface{}{f, st, fd}
+				return Messages{}
+			}
+		}, []interface{}{file, s, field}},
+		{func(args *[]interface{}) interface{} {
+			return func(parent, self interface{}) Messages {
+				*args = []interface{}{parent, self}
+				return Messages{}
+			}
+		}, []interface{}{s, field}},
+		{func(args *[]interface{}) interface{} {
+			return func(self interface{}) Messages {
+				*args = []interface{}{self}
+				return Messages{}
+			}
+		}, []interface{}{field}},
+		{func(args *[]interface{}) interface{} {
+			return func(p *Project, f *parser.Thrift, fd *parser.Field) Messages {
+				*args = []interface{}{p, f, fd}
+				return Messages{}
+			}
+		}, []interface{}{project, file, field}},
+	}
+	for i, test := range tests {
+		var args []interface{}
+		out := callChecker(test.checker(&args), ancestors)
+		if test.expected == nil {
+			require.Nil(t, out, "checker %d", i)
+			continue
+		}
+		require.NotNil(t, out, "checker %d", i)
+		require.Equal(t, len(test.expected), len(args), "checker %d", i)
+		for j := range args {
+			require.True(t, test.expected[j] == args[j], "checker %d argument %d", i, j)
+		}
+	}
 }
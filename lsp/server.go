@@ -0,0 +1,269 @@
+// Package lsp implements a Language Server Protocol server that publishes thriftlint messages as
+// editor diagnostics.
+//
+// Open documents are linted from memory, so diagnostics reflect unsaved changes. Includes are
+// resolved with the include directories configured on the linter, preferring open buffers over
+// files on disk.
+//
+// Only full document synchronisation is supported. Code actions are not offered, as thriftlint
+// checks do not currently provide automatic fixes.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+// ErrExitWithoutShutdown is returned by Serve when the client sends "exit" without first sending
+// "shutdown". Per the protocol, the server should then exit with a non-zero status.
+var ErrExitWithoutShutdown = errors.New("exit requested without shutdown")
+
+// Server is a Language Server Protocol server for thriftlint.
+type Server struct {
+	checks  thriftlint.Checks
+	options []thriftlint.Option
+
+	lock     sync.Mutex
+	w        io.Writer
+	buffers  map[string][]byte
+	shutdown bool
+}
+
+// NewServer creates a new LSP server that lints with the given checks and linter options.
+func NewServer(checks thriftlint.Checks, options ...thriftlint.Option) *Server {
+	return &Server{
+		checks:  checks,
+		options: options,
+		buffers: map[string][]byte{},
+	}
+}
+
+// Serve LSP requests read from r, writing responses and notifications to w.
+//
+// Serve returns nil when the client sends "exit" after "shutdown", or closes r, and
+// ErrExitWithoutShutdown if the client sends "exit" before "shutdown". Requests received after
+// "shutdown" are rejected.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.w = w
+	br := bufio.NewReader(r)
+	for {
+		msg, err := readMessage(br)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return ErrExitWithoutShutdown
+			}
+			return nil
+		}
+		if err := s.handle(msg); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(msg *message) error {
+	var (
+		result interface{}
+		rerr   *responseError
+	)
+	switch {
+	case s.shutdown:
+		// Notifications are dropped and requests rejected once shut down.
+		if msg.ID == nil {
+			return nil
+		}
+		rerr = &responseError{Code: invalidRequest, Message: "server is shut down: " + msg.Method}
+
+	case msg.Method == "initialize":
+		result = &initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync: textDocumentSyncOptions{OpenClose: true, Change: 1, Save: true},
+			},
+			ServerInfo: serverInfo{Name: "thrift-lint"},
+		}
+
+	case msg.Method == "shutdown":
+		s.shutdown = true
+		result = json.RawMessage("null")
+
+	case msg.Method == "textDocument/didOpen":
+		params := &didOpenParams{}
+		if rerr = unmarshalParams(msg, params); rerr == nil {
+			rerr = s.update(params.TextDocument.URI, []byte(params.TextDocument.Text))
+		}
+
+	case msg.Method == "textDocument/didChange":
+		params := &didChangeParams{}
+		if rerr = unmarshalParams(msg, params); rerr == nil && len(params.ContentChanges) > 0 {
+			text := params.ContentChanges[len(params.ContentChanges)-1].Text
+			rerr = s.update(params.TextDocument.URI, []byte(text))
+		}
+
+	case msg.Method == "textDocument/didSave":
+		params := &didSaveParams{}
+		if rerr = unmarshalParams(msg, params); rerr == nil {
+			var text []byte
+			if params.Text != nil {
+				text = []byte(*params.Text)
+			}
+			rerr = s.update(params.TextDocument.URI, text)
+		}
+
+	case msg.Method == "textDocument/didClose":
+		params := &didCloseParams{}
+		if rerr = unmarshalParams(msg, params); rerr == nil {
+			rerr = s.close(params.TextDocument.URI)
+		}
+
+	default:
+		if msg.ID != nil {
+			rerr = &responseError{Code: methodNotFound, Message: "method not found: " + msg.Method}
+		}
+	}
+
+	// Notifications do not have responses.
+	if msg.ID == nil {
+		return nil
+	}
+	return s.write(&message{ID: msg.ID, Result: result, Error: rerr})
+}
+
+func unmarshalParams(msg *message, params interface{}) *responseError {
+	if err := json.Unmarshal(msg.Params, params); err != nil {
+		return &responseError{Code: invalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// Update the buffer for a document, if text is non-nil, then lint it and publish diagnostics.
+func (s *Server) update(uri string, text []byte) *responseError {
+	path, err := uriToPath(uri)
+	if err != nil {
+		return &responseError{Code: invalidParams, Message: err.Error()}
+	}
+	if text != nil {
+		s.buffers[path] = text
+	}
+	diagnostics := s.Lint(path)
+	if err := s.publish(uri, diagnostics); err != nil {
+		return &responseError{Code: invalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+func (s *Server) close(uri string) *responseError {
+	path, err := uriToPath(uri)
+	if err != nil {
+		return &responseError{Code: invalidParams, Message: err.Error()}
+	}
+	delete(s.buffers, path)
+	if err := s.publish(uri, []*Diagnostic{}); err != nil {
+		return &responseError{Code: invalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+func (s *Server) publish(uri string, diagnostics []*Diagnostic) error {
+	params, err := json.Marshal(&PublishDiagnosticsParams{URI: uri, Diagnostics: diagnostics})
+	if err != nil {
+		return err
+	}
+	return s.write(&message{Method: "textDocument/publishDiagnostics", Params: params})
+}
+
+func (s *Server) write(msg *message) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return writeMessage(s.w, msg)
+}
+
+// Lint the document at path, using open buffers in place of files on disk, and return its
+// diagnostics.
+func (s *Server) Lint(path string) []*Diagnostic {
+	overlay := map[string][]byte{}
+	for p, text := range s.buffers {
+		overlay[p] = text
+	}
+	options := append([]thriftlint.Option{}, s.options...)
+	options = append(options, thriftlint.WithOverlay(overlay))
+	diagnostics := []*Diagnostic{}
+	linter, err := thriftlint.New(s.checks, options...)
+	if err != nil {
+		return append(diagnostics, s.errorDiagnostic(path, err))
+	}
+	messages, err := linter.Lint([]string{path})
+	if err != nil {
+		return append(diagnostics, s.errorDiagnostic(path, err))
+	}
+	for _, msg := range messages {
+		if msg.File.Filename != path {
+			continue
+		}
+		severity := severityWarning
+		if msg.Severity == thriftlint.Error {
+			severity = severityError
+		}
+		pos := thriftlint.Pos(msg.Object)
+		diagnostics = append(diagnostics, &Diagnostic{
+			Range:    s.lineRange(path, pos.Line, pos.Col),
+			Severity: severity,
+			Code:     msg.Checker,
+			Source:   "thrift-lint",
+			Message:  msg.Message,
+		})
+	}
+	return diagnostics
+}
+
+// Matches go-thrift parse errors of the form "<file>:<line>:<col> (<offset>): <message>".
+var parseErrorRe = regexp.MustCompile(`^(.*):(\d+):(\d+) \(\d+\): (.*)$`)
+
+// Convert a parse error into a diagnostic, positioned at the error if it is in the document at path.
+func (s *Server) errorDiagnostic(path string, err error) *Diagnostic {
+	text := strings.SplitN(err.Error(), "\n", 2)[0]
+	line, col := 1, 1
+	if groups := parseErrorRe.FindStringSubmatch(text); groups != nil && groups[1] == path {
+		line, _ = strconv.Atoi(groups[2])
+		col, _ = strconv.Atoi(groups[3])
+		text = groups[4]
+	}
+	return &Diagnostic{
+		Range:    s.lineRange(path, line, col),
+		Severity: severityError,
+		Source:   "thrift-lint",
+		Message:  text,
+	}
+}
+
+// Range from a 1-based line and column to the end of that line in the open buffer.
+func (s *Server) lineRange(path string, line, col int) lspRange {
+	if line > 0 {
+		line--
+	}
+	if col > 0 {
+		col--
+	}
+	end := col
+	lines := strings.Split(string(s.buffers[path]), "\n")
+	if line < len(lines) {
+		if n := len([]rune(strings.TrimRight(lines[line], "\r"))); n > end {
+			end = n
+		}
+	}
+	return lspRange{
+		Start: position{Line: line, Character: col},
+		End:   position{Line: line, Character: end},
+	}
+}
@@ -0,0 +1,138 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/alecthomas/go-thrift/parser"
+	"github.com/stretchr/testify/require"
+
+	"github.com/UrbanCompass/thriftlint"
+)
+
+// An in-process LSP client.
+type testClient struct {
+	t    *testing.T
+	w    io.Writer
+	r    *bufio.Reader
+	id   int
+	done chan error
+}
+
+func newTestClient(t *testing.T, server *Server) *testClient {
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Serve(serverR, serverW)
+		serverW.Close()
+	}()
+	return &testClient{t: t, w: clientW, r: bufio.NewReader(clientR), done: done}
+}
+
+func (c *testClient) send(method string, params interface{}, request bool) {
+	body, err := json.Marshal(params)
+	require.NoError(c.t, err)
+	msg := &message{Method: method, Params: body}
+	if request {
+		c.id++
+		id := json.RawMessage(strconv.Itoa(c.id))
+		msg.ID = &id
+	}
+	require.NoError(c.t, writeMessage(c.w, msg))
+}
+
+func (c *testClient) receive() *message {
+	msg, err := readMessage(c.r)
+	require.NoError(c.t, err)
+	return msg
+}
+
+func (c *testClient) diagnostics() *PublishDiagnosticsParams {
+	msg := c.receive()
+	require.Equal(c.t, "textDocument/publishDiagnostics", msg.Method)
+	params := &PublishDiagnosticsParams{}
+	require.NoError(c.t, json.Unmarshal(msg.Params, params))
+	return params
+}
+
+func TestServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "thriftlint-lsp")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	err = ioutil.WriteFile(filepath.Join(dir, "common.thrift"), []byte("struct Common {}\n"), 0600)
+	require.NoError(t, err)
+	path := filepath.Join(dir, "test.thrift")
+	uri := pathToURI(path)
+
+	check := thriftlint.MakeCheck("optional", func(f *parser.Field) (messages thriftlint.Messages) {
+		if !f.Optional {
+			messages.Warning(f, "%s must be optional", f.Name)
+		}
+		return
+	})
+	client := newTestClient(t, NewServer(thriftlint.Checks{check}, thriftlint.WithIncludeDirs(dir)))
+
+	client.send("initialize", map[string]interface{}{}, true)
+	response := client.receive()
+	require.Nil(t, response.Error)
+
+	// The document does not exist on disk, so it must be linted from memory.
+	client.send("textDocument/didOpen", &didOpenParams{TextDocument: textDocumentItem{
+		URI:  uri,
+		Text: "include \"common.thrift\"\n\nstruct Test {\n  1: common.Common common\n}\n",
+	}}, false)
+	diagnostics := client.diagnostics()
+	require.Equal(t, uri, diagnostics.URI)
+	require.Equal(t, 1, len(diagnostics.Diagnostics))
+	diagnostic := diagnostics.Diagnostics[0]
+	require.Equal(t, "common must be optional", diagnostic.Message)
+	require.Equal(t, "optional", diagnostic.Code)
+	require.Equal(t, severityWarning, diagnostic.Severity)
+	require.Equal(t, lspRange{Start: position{Line: 3, Character: 2}, End: position{Line: 3, Character: 25}}, diagnostic.Range)
+
+	text := "include \"common.thrift\"\n\nstruct Test {\n  1: optional common.Common common\n}\n"
+	client.send("textDocument/didChange", map[string]interface{}{
+		"textDocument":   textDocumentIdentifier{URI: uri},
+		"contentChanges": []map[string]string{{"text": text}},
+	}, false)
+	diagnostics = client.diagnostics()
+	require.Equal(t, 0, len(diagnostics.Diagnostics))
+
+	client.send("textDocument/didChange", map[string]interface{}{
+		"textDocument":   textDocumentIdentifier{URI: uri},
+		"contentChanges": []map[string]string{{"text": "struct {"}},
+	}, false)
+	diagnostics = client.diagnostics()
+	require.Equal(t, 1, len(diagnostics.Diagnostics))
+	require.Equal(t, severityError, diagnostics.Diagnostics[0].Severity)
+
+	client.send("textDocument/didClose", &didCloseParams{TextDocument: textDocumentIdentifier{URI: uri}}, false)
+	diagnostics = client.diagnostics()
+	require.Equal(t, 0, len(diagnostics.Diagnostics))
+
+	client.send("shutdown", nil, true)
+	response = client.receive()
+	require.Nil(t, response.Error)
+
+	// Requests after shutdown are rejected.
+	client.send("initialize", map[string]interface{}{}, true)
+	response = client.receive()
+	require.NotNil(t, response.Error)
+	require.Equal(t, invalidRequest, response.Error.Code)
+
+	client.send("exit", nil, false)
+	require.NoError(t, <-client.done)
+}
+
+func TestServerExitWithoutShutdown(t *testing.T) {
+	client := newTestClient(t, NewServer(nil))
+	client.send("exit", nil, false)
+	require.Equal(t, ErrExitWithoutShutdown, <-client.done)
+}
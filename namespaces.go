@@ -0,0 +1,43 @@
+package thriftlint
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/alecthomas/go-thrift/parser"
+)
+
+var namespaceRegex = regexp.MustCompile(`(?m)^[ \t]*namespace[ \t]+([*a-z.-]+)[ \t]+([A-Za-z_][A-Za-z0-9_.]*)`)
+
+// Namespace is a namespace declaration in a Thrift file.
+type Namespace struct {
+	Pos parser.Pos
+	// Scope is the language the namespace applies to, eg. "go", or "*" for all languages.
+	Scope string
+	Value string
+}
+
+// Namespaces returns the namespace declarations of a file in source order, ignoring any in
+// comments.
+//
+// As with Includes, positions are found in source, the Source of the file as parsed. If source is
+// nil, the namespaces are derived from the AST, ordered by scope and without positions.
+func Namespaces(file *parser.Thrift, source *Source) []*Namespace {
+	out := []*Namespace{}
+	if source == nil {
+		for scope, value := range file.Namespaces {
+			out = append(out, &Namespace{Scope: scope, Value: value})
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i].Scope < out[j].Scope })
+		return out
+	}
+	code := source.Code()
+	for _, match := range namespaceRegex.FindAllSubmatchIndex(code, -1) {
+		out = append(out, &Namespace{
+			Pos:   sourcePos(code, match[0]),
+			Scope: string(code[match[2]:match[3]]),
+			Value: string(code[match[4]:match[5]]),
+		})
+	}
+	return out
+}
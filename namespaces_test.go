@@ -0,0 +1,32 @@
+package thriftlint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alecthomas/go-thrift/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaces(t *testing.T) {
+	dir, err := ioutil.TempDir("", "thriftlint-namespaces")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "user.thrift")
+	source := "namespace go acme.user\n/* namespace java commented */\n  namespace java com.acme.user\nnamespace * acme\nstruct User {}\n"
+	require.NoError(t, ioutil.WriteFile(path, []byte(source), 0644))
+
+	project, err := ParseProject(nil, []string{path}, nil)
+	require.NoError(t, err)
+	file := project.Files[path]
+	require.Equal(t, []*Namespace{
+		{Pos: parser.Pos{Line: 1, Col: 1}, Scope: "go", Value: "acme.user"},
+		{Pos: parser.Pos{Line: 3, Col: 3}, Scope: "java", Value: "com.acme.user"},
+		{Pos: parser.Pos{Line: 4, Col: 1}, Scope: "*", Value: "acme"},
+	}, Namespaces(file, project.Source(file)))
+
+	unparsed := &parser.Thrift{Filename: "/missing.thrift", Namespaces: map[string]string{"py": "user", "go": "user"}}
+	require.Equal(t, []*Namespace{{Scope: "go", Value: "user"}, {Scope: "py", Value: "user"}}, Namespaces(unparsed, nil))
+}
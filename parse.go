@@ -1,7 +1,9 @@
 package thriftlint
 
 import (
+	"bytes"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 
@@ -10,8 +12,27 @@ import (
 
 // Parse a set of .thrift source files into their corresponding ASTs.
 func Parse(includeDirs []string, sources []string) (map[string]*parser.Thrift, error) {
+	return ParseWithOverlay(includeDirs, sources, nil)
+}
+
+// ParseWithOverlay parses a set of .thrift source files like Parse, except that the content of any
+// file present in overlay, keyed by absolute path, is read from memory rather than from disk.
+//
+// This is useful for linting unsaved editor buffers.
+func ParseWithOverlay(includeDirs []string, sources []string, overlay map[string][]byte) (map[string]*parser.Thrift, error) {
+	project, err := ParseProject(includeDirs, sources, overlay)
+	if err != nil {
+		return nil, err
+	}
+	return project.Files, nil
+}
+
+// ParseProject parses a set of .thrift source files like ParseWithOverlay, returning a Project
+// that also records the Source of each file as it was parsed.
+func ParseProject(includeDirs []string, sources []string, overlay map[string][]byte) (*Project, error) {
+	fs := &includeFilesystem{IncludeDirs: includeDirs, Overlay: overlay, sources: map[string]*Source{}}
 	p := parser.New()
-	p.Filesystem = &includeFilesystem{IncludeDirs: includeDirs}
+	p.Filesystem = fs
 
 	var files map[string]*parser.Thrift
 	for _, path := range sources {
@@ -30,22 +51,30 @@ func Parse(includeDirs []string, sources []string) (map[string]*parser.Thrift, e
 			file.Imports[symbol] = files[path]
 		}
 	}
-	return files, nil
+	project := NewProject(files)
+	project.fs = fs
+	project.sources = fs.sources
+	return project, nil
 }
 
 // A go-thrift/parser.Filesystem implementation that searches include dirs when attempting to open
 // sources.
 type includeFilesystem struct {
 	IncludeDirs []string
+	// Overlay of file content keyed by absolute path.
+	Overlay map[string][]byte
+
+	// Content of each file opened, keyed by absolute path.
+	sources map[string]*Source
 }
 
 func (i *includeFilesystem) Open(filename string) (io.ReadCloser, error) {
 	if filepath.IsAbs(filename) {
-		return os.Open(filename)
+		return i.open(filename)
 	}
 	for _, d := range i.IncludeDirs {
 		path := filepath.Join(d, filename)
-		r, err := os.Open(path)
+		r, err := i.open(path)
 		if os.IsNotExist(err) {
 			continue
 		} else if err != nil {
@@ -65,9 +94,46 @@ func (i *includeFilesystem) Abs(dir, path string) (string, error) {
 		if err != nil {
 			continue
 		}
-		if _, err := os.Stat(p); err == nil {
+		if i.exists(p) {
 			return filepath.Abs(p)
 		}
 	}
 	return filepath.Abs(filepath.Join(dir, path))
 }
+
+// Open path from the overlay or disk, recording its content so that checks see exactly what was
+// parsed.
+func (i *includeFilesystem) open(path string) (io.ReadCloser, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	content, ok := i.Overlay[abs]
+	if !ok {
+		if content, err = ioutil.ReadFile(abs); err != nil {
+			return nil, err
+		}
+	}
+	if i.sources != nil {
+		i.sources[abs] = NewSource(content)
+	}
+	return &namedReadCloser{ReadCloser: ioutil.NopCloser(bytes.NewReader(content)), name: abs}, nil
+}
+
+func (i *includeFilesystem) exists(path string) bool {
+	if abs, err := filepath.Abs(path); err == nil {
+		if _, ok := i.Overlay[abs]; ok {
+			return true
+		}
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// go-thrift uses the Name() of the reader as the filename of the parsed AST.
+type namedReadCloser struct {
+	io.ReadCloser
+	name string
+}
+
+func (n *namedReadCloser) Name() string { return n.name }
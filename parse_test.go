@@ -0,0 +1,21 @@
+package thriftlint
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncludeFilesystemOverlayRelativePath(t *testing.T) {
+	abs, err := filepath.Abs("overlay.thrift")
+	require.NoError(t, err)
+	fs := &includeFilesystem{
+		IncludeDirs: []string{"."},
+		Overlay:     map[string][]byte{abs: []byte("struct Overlay {}\n")},
+	}
+	require.True(t, fs.exists("overlay.thrift"))
+	path, err := fs.Abs("/elsewhere", "overlay.thrift")
+	require.NoError(t, err)
+	require.Equal(t, abs, path)
+}
@@ -0,0 +1,87 @@
+package thriftlint
+
+import (
+	"path/filepath"
+	"sync"
+
+	"github.com/alecthomas/go-thrift/parser"
+)
+
+// Project is the set of all files parsed in a single Lint, including those reached through
+// includes.
+//
+// The linter passes the Project as the first ancestor of every node, so checks that need a view
+// of the whole project can accept it as their first argument, eg.
+//
+//	func(project *Project, file *parser.Thrift, s *parser.Struct) Messages
+type Project struct {
+	// Files keyed by absolute path, as returned by Parse.
+	Files map[string]*parser.Thrift
+
+	// Filesystem the files were parsed from, and the Source of each, keyed by absolute path.
+	fs      *includeFilesystem
+	sources map[string]*Source
+
+	lock   sync.Mutex
+	index  *Index
+	values map[interface{}]*projectValue
+}
+
+type projectValue struct {
+	once  sync.Once
+	value interface{}
+}
+
+// NewProject creates a Project from the output of Parse.
+func NewProject(files map[string]*parser.Thrift) *Project {
+	return &Project{Files: files, values: map[interface{}]*projectValue{}}
+}
+
+// Source of file as it was parsed, or nil if it is not known, eg. for a Project created with
+// NewProject.
+func (p *Project) Source(file *parser.Thrift) *Source {
+	return p.sources[file.Filename]
+}
+
+// IncludePath returns the absolute path that include, an include statement of file, resolves to.
+//
+// Unlike file.Includes, which is keyed by name, this distinguishes files with the same base name
+// in different directories.
+func (p *Project) IncludePath(file *parser.Thrift, include *Include) string {
+	if p.fs != nil {
+		if path, err := p.fs.Abs(filepath.Dir(file.Filename), include.Path); err == nil {
+			return path
+		}
+	}
+	if path, ok := file.Includes[include.Name]; ok {
+		return path
+	}
+	return include.Path
+}
+
+// Index of the definitions and references in the project, built on first use.
+func (p *Project) Index() *Index {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.index == nil {
+		p.index = NewIndex(p.Files)
+	}
+	return p.index
+}
+
+// Value returns the value cached under key, calling compute to create it on first use.
+//
+// This allows checks to compute project-wide state once per Lint rather than once per node. Keys
+// should be of an unexported type to avoid collisions, as with context.Context. compute is called
+// at most once per key, with concurrent callers waiting for its result.
+func (p *Project) Value(key interface{}, compute func() interface{}) interface{} {
+	p.lock.Lock()
+	value, ok := p.values[key]
+	if !ok {
+		value = &projectValue{}
+		p.values[key] = value
+	}
+	p.lock.Unlock()
+	value.once.Do(func() { value.value = compute() })
+	return value.value
+}
@@ -0,0 +1,58 @@
+package thriftlint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/go-thrift/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintProject(t *testing.T) {
+	dir, err := ioutil.TempDir("", "thriftlint-project")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	common := filepath.Join(dir, "common.thrift")
+	service := filepath.Join(dir, "service.thrift")
+	require.NoError(t, ioutil.WriteFile(common, []byte("struct User {}\n"), 0644))
+	require.NoError(t, ioutil.WriteFile(service, []byte("include \"common.thrift\"\nstruct Request {}\n"), 0644))
+
+	var projects []*Project
+	check := MakeCheck("project", func(project *Project, file *parser.Thrift) (messages Messages) {
+		projects = append(projects, project)
+		require.Equal(t, file, project.Files[file.Filename])
+		return
+	})
+	linter, err := New([]Check{check})
+	require.NoError(t, err)
+	_, err = linter.Lint([]string{service})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(projects))
+	require.True(t, projects[0] == projects[1])
+	require.Equal(t, 2, len(projects[0].Index().Definitions))
+}
+
+func TestProjectValue(t *testing.T) {
+	project := NewProject(map[string]*parser.Thrift{})
+	calls := int32(0)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value := project.Value("key", func() interface{} {
+				time.Sleep(10 * time.Millisecond)
+				return atomic.AddInt32(&calls, 1)
+			})
+			require.Equal(t, int32(1), value)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), calls)
+	require.Equal(t, "other", project.Value("other", func() interface{} { return "other" }))
+}
@@ -0,0 +1,231 @@
+package thriftlint
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CompatibilityMode selects which recorded versions a schema is checked against, and in which
+// direction.
+type CompatibilityMode int
+
+// Compatibility modes.
+const (
+	// Backward checks that readers using the new schema can read data written with the latest
+	// recorded version.
+	Backward CompatibilityMode = iota
+	// Forward checks that readers using the latest recorded version can read data written with the
+	// new schema.
+	Forward
+	// Full checks both Backward and Forward compatibility with the latest recorded version.
+	Full
+	// Transitive checks Full compatibility with every recorded version.
+	Transitive
+)
+
+// ParseCompatibilityMode parses the name of a CompatibilityMode, eg. "backward".
+func ParseCompatibilityMode(name string) (CompatibilityMode, error) {
+	for _, mode := range []CompatibilityMode{Backward, Forward, Full, Transitive} {
+		if mode.String() == name {
+			return mode, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown compatibility mode %q", name)
+}
+
+func (c CompatibilityMode) String() string {
+	switch c {
+	case Forward:
+		return "forward"
+	case Full:
+		return "full"
+	case Transitive:
+		return "transitive"
+	}
+	return "backward"
+}
+
+// Registry of released schema snapshots, stored as plain files so it can be checked in.
+//
+// Each subject, typically a service, is stored in its own directory. A "versions" file in that
+// directory lists the recorded versions in order, one per line, and each version is a copy of the
+// .thrift files of the schema tree in a directory named after the version. Files the schema
+// includes from include directories outside the tree are stored alongside, relative to their
+// include directory, so that recorded versions do not depend on the current include directories:
+//
+//	<dir>/<subject>/versions
+//	<dir>/<subject>/<version>/<path>.thrift
+//	<dir>/<subject>/<version>.includes/<path>.thrift
+type Registry struct {
+	Dir string
+}
+
+// RegistryCheck is the result of comparing a schema against one recorded version.
+type RegistryCheck struct {
+	Version string
+	// Direction is Backward, where changes are classified by whether readers using the schema can
+	// read data written with the recorded version, or Forward, for the reverse.
+	Direction CompatibilityMode
+	// Changes from the recorded version to the schema.
+	Changes Changes
+}
+
+const (
+	registryVersionsFile   = "versions"
+	registryIncludesSuffix = ".includes"
+)
+
+// Versions recorded for subject, oldest first.
+func (r *Registry) Versions(subject string) ([]string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(r.Dir, subject, registryVersionsFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	versions := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			versions = append(versions, line)
+		}
+	}
+	return versions, nil
+}
+
+// Add records the schema tree under root as a new version of subject.
+//
+// The schema must parse, with includes resolved relative to root followed by includeDirs. The
+// registry directory is skipped if it is under root. The version is written to a temporary
+// directory first, so a failed Add does not leave a partial version behind.
+func (r *Registry) Add(subject, version, root string, includeDirs []string) error {
+	if err := checkRegistryName("subject", subject); err != nil {
+		return err
+	}
+	if err := checkRegistryName("version", version); err != nil {
+		return err
+	}
+	versions, err := r.Versions(subject)
+	if err != nil {
+		return err
+	}
+	for _, existing := range versions {
+		if existing == version {
+			return fmt.Errorf("version %q of %s is already recorded", version, subject)
+		}
+	}
+	dest := filepath.Join(r.Dir, subject, version)
+	for _, path := range []string{dest, dest + registryIncludesSuffix} {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists", path)
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+	files, err := parseDirExcluding(root, includeDirs, r.Dir)
+	if err != nil {
+		return err
+	}
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempDir(filepath.Dir(dest), "."+version+".")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	tree := filepath.Join(tmp, "tree")
+	includes := filepath.Join(tmp, "includes")
+	for path := range files {
+		target := ""
+		if rel, ok := relativePath(root, path); ok {
+			target = filepath.Join(tree, rel)
+		} else if rel, ok := includeRelativePath(includeDirs, path); ok {
+			target = filepath.Join(includes, rel)
+		} else {
+			return fmt.Errorf("%s is outside the schema tree and include directories", path)
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err = ioutil.WriteFile(target, data, 0644); err != nil {
+			return err
+		}
+	}
+	if _, err = os.Stat(includes); err == nil {
+		if err = os.Rename(includes, dest+registryIncludesSuffix); err != nil {
+			return err
+		}
+	}
+	if err = os.Rename(tree, dest); err != nil {
+		os.RemoveAll(dest + registryIncludesSuffix)
+		return err
+	}
+	versions = append(versions, version)
+	return ioutil.WriteFile(filepath.Join(r.Dir, subject, registryVersionsFile),
+		[]byte(strings.Join(versions, "\n")+"\n"), 0644)
+}
+
+// Check the schema tree under root against the recorded versions of subject selected by mode.
+//
+// The registry directory is skipped if it is under root.
+func (r *Registry) Check(subject, root string, mode CompatibilityMode, includeDirs []string) ([]*RegistryCheck, error) {
+	versions, err := r.Versions(subject)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no versions of %s are recorded", subject)
+	}
+	if mode != Transitive {
+		versions = versions[len(versions)-1:]
+	}
+	directions := []CompatibilityMode{mode}
+	if mode == Full || mode == Transitive {
+		directions = []CompatibilityMode{Backward, Forward}
+	}
+	current, err := parseDirExcluding(root, includeDirs, r.Dir)
+	if err != nil {
+		return nil, err
+	}
+	out := []*RegistryCheck{}
+	for _, version := range versions {
+		dir := filepath.Join(r.Dir, subject, version)
+		// Versions recorded without their includes use the current include directories.
+		recordedIncludeDirs := includeDirs
+		if info, err := os.Stat(dir + registryIncludesSuffix); err == nil && info.IsDir() {
+			recordedIncludeDirs = []string{dir + registryIncludesSuffix}
+		}
+		recorded, err := ParseDir(dir, recordedIncludeDirs)
+		if err != nil {
+			return nil, err
+		}
+		for _, direction := range directions {
+			changes, err := compareTrees(&schemaTree{dir, recordedIncludeDirs, recorded},
+				&schemaTree{root, includeDirs, current}, false, direction)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, &RegistryCheck{Version: version, Direction: direction, Changes: changes})
+		}
+	}
+	return out, nil
+}
+
+func checkRegistryName(what, name string) error {
+	if name == "" || name == "." || name == ".." || name == registryVersionsFile || strings.ContainsAny(name, `/\`) ||
+		strings.HasSuffix(name, registryIncludesSuffix) {
+		return fmt.Errorf("invalid %s %q", what, name)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package thriftlint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeSchema(t *testing.T, dir, source string) {
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "user"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "user", "user.thrift"), []byte(source), 0644))
+}
+
+func wireBreaking(checks []*RegistryCheck) []string {
+	out := []string{}
+	for _, check := range checks {
+		for _, change := range check.Changes {
+			if change.Compatibility == WireBreaking {
+				out = append(out, check.Version+" "+check.Direction.String()+": "+change.Message)
+			}
+		}
+	}
+	return out
+}
+
+func TestRegistry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "thriftlint-registry")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	registry := &Registry{Dir: filepath.Join(dir, "registry")}
+	root := filepath.Join(dir, "schema")
+
+	writeSchema(t, root, "struct User {\n  1: required string id\n  2: optional string name\n}\n")
+	require.NoError(t, registry.Add("users", "1.0", root, nil))
+	require.Error(t, registry.Add("users", "1.0", root, nil))
+	require.Error(t, registry.Add("users", "../1.1", root, nil))
+
+	writeSchema(t, root, "struct User {\n  1: required string id\n}\n")
+	require.NoError(t, registry.Add("users", "1.1", root, nil))
+
+	versions, err := registry.Versions("users")
+	require.NoError(t, err)
+	require.Equal(t, []string{"1.0", "1.1"}, versions)
+	recorded, err := ioutil.ReadFile(filepath.Join(dir, "registry", "users", "1.0", "user", "user.thrift"))
+	require.NoError(t, err)
+	require.Contains(t, string(recorded), "name")
+
+	writeSchema(t, root, "struct User {\n  1: required string id\n  3: required string email\n}\n")
+
+	checks, err := registry.Check("users", root, Backward, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{`1.1 backward: field 3 "email" of user/user.User was added`}, wireBreaking(checks))
+
+	// Old readers ignore the new required field.
+	checks, err = registry.Check("users", root, Forward, nil)
+	require.NoError(t, err)
+	require.Empty(t, wireBreaking(checks))
+
+	checks, err = registry.Check("users", root, Full, nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(checks))
+	require.Equal(t, []string{`1.1 backward: field 3 "email" of user/user.User was added`}, wireBreaking(checks))
+
+	// Removing a required field breaks old readers, but not new ones.
+	writeSchema(t, root, "struct User {\n  2: optional string name\n}\n")
+	checks, err = registry.Check("users", root, Backward, nil)
+	require.NoError(t, err)
+	require.Empty(t, wireBreaking(checks))
+	checks, err = registry.Check("users", root, Forward, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{`1.1 forward: field 1 "id" of user/user.User was removed`}, wireBreaking(checks))
+
+	checks, err = registry.Check("users", root, Transitive, nil)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(checks))
+	require.Equal(t, "1.0", checks[0].Version)
+
+	_, err = registry.Check("missing", root, Backward, nil)
+	require.Error(t, err)
+}
+
+func TestRegistryIncludes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "thriftlint-registry")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	registry := &Registry{Dir: filepath.Join(dir, "registry")}
+	root := filepath.Join(dir, "schema")
+	include := filepath.Join(dir, "include")
+	writeInclude := func(source string) {
+		require.NoError(t, os.MkdirAll(include, 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(include, "common.thrift"), []byte(source), 0644))
+	}
+
+	writeInclude("struct Common {\n  1: required string id\n}\n")
+	writeSchema(t, root, "include \"common.thrift\"\nstruct User {\n  1: required common.Common common\n}\n")
+	require.NoError(t, registry.Add("users", "1.0", root, []string{include}))
+	recorded, err := ioutil.ReadFile(filepath.Join(dir, "registry", "users", "1.0.includes", "common.thrift"))
+	require.NoError(t, err)
+	require.Contains(t, string(recorded), "Common")
+	require.Error(t, registry.Add("users", "2.0.includes", root, []string{include}))
+
+	// The recorded version is compared against its own copy of the include.
+	writeInclude("struct Common {\n  1: required string id\n  2: required string name\n}\n")
+	checks, err := registry.Check("users", root, Backward, []string{include})
+	require.NoError(t, err)
+	require.Equal(t, []string{`1.0 backward: field 2 "name" of common.Common was added`}, wireBreaking(checks))
+}
+
+func TestRegistryUnderRoot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "thriftlint-registry")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	// The registry is checked in alongside the schema it records.
+	registry := &Registry{Dir: filepath.Join(dir, "registry")}
+
+	writeSchema(t, dir, "struct User {\n  1: required string id\n}\n")
+	require.NoError(t, registry.Add("users", "1.0", dir, nil))
+	writeSchema(t, dir, "struct User {\n  1: required string id\n  2: optional string name\n}\n")
+	require.NoError(t, registry.Add("users", "2.0", dir, nil))
+
+	files := []string{}
+	err = filepath.Walk(filepath.Join(dir, "registry", "users", "2.0"), func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			rel, _ := filepath.Rel(dir, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"registry/users/2.0/user/user.thrift"}, files)
+
+	checks, err := registry.Check("users", dir, Transitive, nil)
+	require.NoError(t, err)
+	messages := []string{}
+	for _, check := range checks {
+		for _, change := range check.Changes {
+			messages = append(messages, check.Version+" "+check.Direction.String()+": "+change.Message)
+		}
+	}
+	require.Equal(t, []string{
+		`1.0 backward: field 2 "name" of user/user.User was added`,
+		`1.0 forward: field 2 "name" of user/user.User was added`,
+	}, messages)
+}
+
+func TestRegistryAddFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "thriftlint-registry")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	registry := &Registry{Dir: filepath.Join(dir, "registry")}
+	root := filepath.Join(dir, "schema")
+
+	// Version directories that are not listed in the versions file are not overwritten.
+	writeSchema(t, root, "struct User {}\n")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "registry", "users", "1.0"), 0755))
+	require.Error(t, registry.Add("users", "1.0", root, nil))
+
+	// Files outside the tree and include directories can not be recorded.
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "outside.thrift"), []byte("struct Outside {}\n"), 0644))
+	writeSchema(t, root, "include \"../outside.thrift\"\nstruct User {\n  1: optional outside.Outside outside\n}\n")
+	require.Error(t, registry.Add("users", "2.0", root, nil))
+	entries, err := ioutil.ReadDir(filepath.Join(dir, "registry", "users"))
+	require.NoError(t, err)
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	require.Equal(t, []string{"1.0"}, names)
+	versions, err := registry.Versions("users")
+	require.NoError(t, err)
+	require.Empty(t, versions)
+}
@@ -0,0 +1,96 @@
+package thriftlint
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/alecthomas/go-thrift/parser"
+)
+
+// Source is the content of a Thrift file, exactly as it was parsed.
+//
+// This gives checks access to syntax that is not preserved in the AST, such as the positions of
+// includes. All methods may be called on a nil Source, which behaves as an empty file.
+type Source struct {
+	Content []byte
+
+	lines []string
+	// Byte offset of the start of each line.
+	offsets []int
+	code    []byte
+}
+
+// NewSource creates a Source from file content.
+func NewSource(content []byte) *Source {
+	lines := strings.Split(string(content), "\n")
+	offsets := make([]int, len(lines))
+	offset := 0
+	for i, line := range lines {
+		offsets[i] = offset
+		offset += len(line) + 1
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return &Source{Content: content, lines: lines, offsets: offsets, code: blankComments(content)}
+}
+
+// Line returns line n (starting at 1) of the source, or "" if there is no such line.
+func (s *Source) Line(n int) string {
+	if s == nil || n < 1 || n > len(s.lines) {
+		return ""
+	}
+	return s.lines[n-1]
+}
+
+// Code returns the content with comments replaced by spaces. Line breaks are preserved, so offsets
+// and positions are the same as in Content, but commented out syntax can not be mistaken for code.
+func (s *Source) Code() []byte {
+	if s == nil {
+		return nil
+	}
+	return s.code
+}
+
+// Offset returns the byte offset of pos in the content, or -1 if it is out of range.
+func (s *Source) Offset(pos parser.Pos) int {
+	if s == nil || pos.Line < 1 || pos.Line > len(s.lines) || pos.Col < 1 {
+		return -1
+	}
+	if pos.Col-1 > len(s.lines[pos.Line-1]) {
+		return -1
+	}
+	return s.offsets[pos.Line-1] + pos.Col - 1
+}
+
+// Replace "//", "#" and "/* */" comments in content with spaces, preserving line breaks. Comment
+// characters inside string literals are left alone.
+func blankComments(content []byte) []byte {
+	out := make([]byte, len(content))
+	copy(out, content)
+	for i := 0; i < len(out); i++ {
+		switch {
+		case out[i] == '"' || out[i] == '\'':
+			quote := out[i]
+			for i++; i < len(out) && out[i] != quote && out[i] != '\n'; i++ {
+			}
+		case out[i] == '#' || (out[i] == '/' && i+1 < len(out) && out[i+1] == '/'):
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				if out[i] != '\r' {
+					out[i] = ' '
+				}
+			}
+		case out[i] == '/' && i+1 < len(out) && out[i+1] == '*':
+			// The end of the comment, or of the content if it is not closed.
+			end := len(out)
+			if n := bytes.Index(out[i+2:], []byte("*/")); n >= 0 {
+				end = i + 2 + n + 2
+			}
+			for ; i < end; i++ {
+				if out[i] != '\n' {
+					out[i] = ' '
+				}
+			}
+			i--
+		}
+	}
+	return out
+}
@@ -0,0 +1,44 @@
+package thriftlint
+
+import (
+	"testing"
+
+	"github.com/alecthomas/go-thrift/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSource(t *testing.T) {
+	source := NewSource([]byte("struct A {} // \"a\"\r\n/* b\n c */ const string C = \"# /* c\"\n# d\n"))
+	require.Equal(t, "struct A {} // \"a\"", source.Line(1))
+	require.Equal(t, " c */ const string C = \"# /* c\"", source.Line(3))
+	require.Equal(t, "", source.Line(0))
+	require.Equal(t, "", source.Line(6))
+
+	require.Equal(t, "struct A {}       \r\n    \n      const string C = \"# /* c\"\n   \n", string(source.Code()))
+
+	require.Equal(t, 0, source.Offset(parser.Pos{Line: 1, Col: 1}))
+	require.Equal(t, 30, source.Offset(parser.Pos{Line: 3, Col: 6}))
+	require.Equal(t, -1, source.Offset(parser.Pos{Line: 3, Col: 0}))
+	require.Equal(t, -1, source.Offset(parser.Pos{Line: 2, Col: 6}))
+	require.Equal(t, -1, source.Offset(parser.Pos{Line: 7, Col: 1}))
+
+	// A nil Source is empty.
+	var unknown *Source
+	require.Equal(t, "", unknown.Line(1))
+	require.Nil(t, unknown.Code())
+	require.Equal(t, -1, unknown.Offset(parser.Pos{Line: 1, Col: 1}))
+}
+
+func TestParseProjectSources(t *testing.T) {
+	overlay := map[string][]byte{
+		"/thriftlint-test/main.thrift":   []byte("include \"common.thrift\"\n"),
+		"/thriftlint-test/common.thrift": []byte("struct Common {}\n"),
+	}
+	project, err := ParseProject([]string{"/thriftlint-test"}, []string{"/thriftlint-test/main.thrift"}, overlay)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(project.Files))
+	for path, file := range project.Files {
+		require.Equal(t, overlay[path], project.Source(file).Content)
+	}
+	require.Nil(t, NewProject(project.Files).Source(project.Files["/thriftlint-test/main.thrift"]))
+}
@@ -0,0 +1,143 @@
+package thriftlint
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Stats summarises a set of messages by check, file and severity.
+type Stats struct {
+	Checks     map[string]int
+	Files      map[string]int
+	Severities map[Severity]int
+}
+
+// NewStats creates empty Stats.
+func NewStats() *Stats {
+	return &Stats{
+		Checks:     map[string]int{},
+		Files:      map[string]int{},
+		Severities: map[Severity]int{},
+	}
+}
+
+// Add a message to the counts.
+func (s *Stats) Add(msg *Message) {
+	s.Checks[msg.Checker]++
+	s.Files[msg.File.Filename]++
+	s.Severities[msg.Severity]++
+}
+
+// Write the counts to w, each group in descending order.
+func (s *Stats) Write(w io.Writer) {
+	fmt.Fprintf(w, "By severity:\n")
+	for _, severity := range []Severity{Error, Warning} {
+		fmt.Fprintf(w, "  %6d  %s\n", s.Severities[severity], severity)
+	}
+	fmt.Fprintf(w, "By check:\n")
+	writeCounts(w, s.Checks)
+	fmt.Fprintf(w, "By file:\n")
+	writeCounts(w, s.Files)
+}
+
+// Write counts in descending order, breaking ties by key.
+func writeCounts(w io.Writer, counts map[string]int) {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	for _, key := range keys {
+		fmt.Fprintf(w, "  %6d  %s\n", counts[key], key)
+	}
+}
+
+// ExitPolicy decides the exit status of a lint run.
+//
+// The exit status is a bitmask of the severities of failing messages, eg. 1 for warnings and 2 for
+// errors.
+type ExitPolicy struct {
+	// Minimum severity that fails, or nil to only fail on checks and warning limits.
+	FailOn *Severity
+	// Maximum number of warnings before failing, or -1 for no limit. When set, warnings only fail
+	// once there are more than MaxWarnings of them.
+	MaxWarnings int
+	// Check ID prefixes that always fail.
+	FailOnChecks []string
+}
+
+// NewExitPolicy creates an ExitPolicy from its command line form.
+//
+// failOn is one of "warning", "error" or "never", and failOnChecks are comma separated lists of
+// check ID prefixes.
+func NewExitPolicy(failOn string, maxWarnings int, failOnChecks []string) (*ExitPolicy, error) {
+	policy := &ExitPolicy{MaxWarnings: maxWarnings}
+	switch failOn {
+	case "warning":
+		severity := Warning
+		policy.FailOn = &severity
+	case "error":
+		severity := Error
+		policy.FailOn = &severity
+	case "never":
+	default:
+		return nil, fmt.Errorf("unknown severity %q, expected one of warning, error or never", failOn)
+	}
+	for _, checks := range failOnChecks {
+		for _, check := range strings.Split(checks, ",") {
+			if check = strings.TrimSpace(check); check != "" {
+				policy.FailOnChecks = append(policy.FailOnChecks, check)
+			}
+		}
+	}
+	return policy, nil
+}
+
+// Status returns the exit status for messages.
+//
+// messages should be every message from the run, not only those that are displayed, so that
+// warnings are counted against MaxWarnings.
+func (p *ExitPolicy) Status(messages Messages) int {
+	status := 0
+	warnings := 0
+	for _, msg := range messages {
+		if msg.Severity == Info {
+			continue
+		}
+		if msg.Severity == Warning {
+			warnings++
+		}
+		if p.failsCheck(msg.Checker) {
+			status |= 1 << uint(msg.Severity)
+			continue
+		}
+		if p.FailOn == nil || msg.Severity < *p.FailOn {
+			continue
+		}
+		// Warnings are governed by the limit if there is one.
+		if msg.Severity == Warning && p.MaxWarnings >= 0 {
+			continue
+		}
+		status |= 1 << uint(msg.Severity)
+	}
+	if p.MaxWarnings >= 0 && warnings > p.MaxWarnings {
+		status |= 1 << uint(Warning)
+	}
+	return status
+}
+
+func (p *ExitPolicy) failsCheck(id string) bool {
+	for _, prefix := range p.FailOnChecks {
+		if prefix == id || strings.HasPrefix(id, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package thriftlint
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/alecthomas/go-thrift/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func testMessages(severities ...Severity) Messages {
+	messages := Messages{}
+	for _, severity := range severities {
+		messages = append(messages, &Message{Severity: severity, Checker: "naming"})
+	}
+	return messages
+}
+
+func TestStats(t *testing.T) {
+	a := &parser.Thrift{Filename: "a.thrift"}
+	b := &parser.Thrift{Filename: "b.thrift"}
+	stats := NewStats()
+	stats.Add(&Message{File: a, Checker: "naming", Severity: Warning})
+	stats.Add(&Message{File: b, Checker: "naming", Severity: Error})
+	stats.Add(&Message{File: b, Checker: "map", Severity: Warning})
+	w := &bytes.Buffer{}
+	stats.Write(w)
+	require.Equal(t, `By severity:
+       1  error
+       2  warning
+By check:
+       2  naming
+       1  map
+By file:
+       2  b.thrift
+       1  a.thrift
+`, w.String())
+}
+
+func TestExitPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		failOn       string
+		maxWarnings  int
+		failOnChecks []string
+		messages     Messages
+		status       int
+	}{
+		{"Clean", "warning", -1, nil, nil, 0},
+		{"Warning", "warning", -1, nil, testMessages(Warning), 1},
+		{"WarningAndError", "warning", -1, nil, testMessages(Warning, Error), 3},
+		{"FailOnErrorIgnoresWarnings", "error", -1, nil, testMessages(Warning, Warning), 0},
+		{"FailOnError", "error", -1, nil, testMessages(Warning, Error), 2},
+		{"Never", "never", -1, nil, testMessages(Warning, Error), 0},
+		{"WithinWarningLimit", "warning", 2, nil, testMessages(Warning, Warning), 0},
+		{"PastWarningLimit", "warning", 2, nil, testMessages(Warning, Warning, Warning), 1},
+		{"WarningLimitWithErrors", "warning", 2, nil, testMessages(Warning, Error), 2},
+		{"WarningLimitWithNever", "never", 0, nil, testMessages(Warning), 1},
+		{"FailOnCheck", "never", -1, []string{"map,naming"}, testMessages(Warning), 1},
+		{"FailOnCheckWithinLimit", "warning", 5, []string{"naming"}, testMessages(Warning), 1},
+		{"FailOnOtherCheck", "never", -1, []string{"nam"}, testMessages(Warning), 0},
+		{"Info", "warning", 0, []string{"naming"}, testMessages(Info, Info), 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy, err := NewExitPolicy(test.failOn, test.maxWarnings, test.failOnChecks)
+			require.NoError(t, err)
+			require.Equal(t, test.status, policy.Status(test.messages))
+		})
+	}
+
+	_, err := NewExitPolicy("info", -1, nil)
+	require.Error(t, err)
+}
+
+func TestExitPolicyFailOnCheckPrefix(t *testing.T) {
+	policy, err := NewExitPolicy("never", -1, []string{" naming , "})
+	require.NoError(t, err)
+	require.Equal(t, []string{"naming"}, policy.FailOnChecks)
+	messages := Messages{{Checker: "naming.field", Severity: Error}}
+	require.Equal(t, 2, policy.Status(messages))
+}
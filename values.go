@@ -0,0 +1,228 @@
+package thriftlint
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/alecthomas/go-thrift/parser"
+)
+
+// Ranges of the integer types.
+var integerRanges = map[string][2]int64{
+	"byte": {math.MinInt8, math.MaxInt8},
+	"i8":   {math.MinInt8, math.MaxInt8},
+	"i16":  {math.MinInt16, math.MaxInt16},
+	"i32":  {math.MinInt32, math.MaxInt32},
+	"i64":  {math.MinInt64, math.MaxInt64},
+}
+
+// TypeCheckValue checks that a constant value or field default appearing in file is valid for
+// type t.
+//
+// Literals are checked recursively against t, resolved through typedefs, including the elements of
+// lists, sets and maps, and the fields of structs. References to constants are checked against
+// the value of the constant, and references to enum values must belong to the expected enum.
+//
+// Unknown types and unknown constants are not reported, as they are reported by the "types" and
+// "references" checks.
+func TypeCheckValue(t *parser.Type, value interface{}, file *parser.Thrift) error {
+	return typeCheckValue(t, file, value, file, map[*parser.Constant]bool{})
+}
+
+// Check value appearing in valueFile against t appearing in typeFile.
+func typeCheckValue(t *parser.Type, typeFile *parser.Thrift, value interface{}, valueFile *parser.Thrift, seen map[*parser.Constant]bool) error {
+	resolved, err := ResolveType(t, typeFile)
+	if err != nil {
+		return nil
+	}
+	if symbol, ok := value.(parser.Identifier); ok && symbol != "true" && symbol != "false" {
+		definition, definitionFile, kind := ResolveDefinition(string(symbol), valueFile)
+		switch kind {
+		case ConstantKind:
+			constant := definition.(*parser.Constant)
+			if seen[constant] {
+				return constantCycleError(constant.Name)
+			}
+			seen[constant] = true
+			defer delete(seen, constant)
+			err := typeCheckValue(t, typeFile, constant.Value, definitionFile, seen)
+			if _, ok := err.(constantCycleError); err != nil && !ok {
+				return fmt.Errorf("constant %s: %s", symbol, err)
+			}
+			return err
+		case EnumValueKind:
+			return typeCheckEnumValue(resolved, definition.(*parser.EnumValue), string(symbol))
+		}
+		return nil
+	}
+
+	expected := typeName(resolved.Type)
+	mismatch := func() error { return fmt.Errorf("expected %s, got %s", expected, describeValue(value)) }
+	switch resolved.Kind {
+	case BuiltinKind:
+		switch name := resolved.Type.Name; name {
+		case "bool":
+			switch value := value.(type) {
+			case parser.Identifier:
+				return nil
+			case int64:
+				if value == 0 || value == 1 {
+					return nil
+				}
+			}
+			return mismatch()
+		case "double":
+			switch value.(type) {
+			case int64, float64:
+				return nil
+			}
+			return mismatch()
+		case "string", "binary":
+			if _, ok := value.(string); !ok {
+				return mismatch()
+			}
+			return nil
+		default:
+			n, ok := value.(int64)
+			if !ok {
+				return mismatch()
+			}
+			if r, ok := integerRanges[name]; ok && (n < r[0] || n > r[1]) {
+				return fmt.Errorf("%d is out of range for %s", n, name)
+			}
+			return nil
+		}
+
+	case EnumKind:
+		n, ok := value.(int64)
+		if !ok {
+			return mismatch()
+		}
+		for _, v := range resolved.Definition.(*parser.Enum).Values {
+			if int64(v.Value) == n {
+				return nil
+			}
+		}
+		return fmt.Errorf("%d is not a value of enum %s", n, expected)
+
+	case ContainerKind:
+		if resolved.Type.Name == "map" {
+			if value == nil {
+				return nil
+			}
+			kvs, ok := value.([]parser.KeyValue)
+			if !ok {
+				return mismatch()
+			}
+			for _, kv := range kvs {
+				if err := typeCheckValue(resolved.Type.KeyType, resolved.File, kv.Key, valueFile, seen); err != nil {
+					return fmt.Errorf("key %s: %s", describeValue(kv.Key), err)
+				}
+				if err := typeCheckValue(resolved.Type.ValueType, resolved.File, kv.Value, valueFile, seen); err != nil {
+					return fmt.Errorf("value of key %s: %s", describeValue(kv.Key), err)
+				}
+			}
+			return nil
+		}
+		elements, ok := value.([]interface{})
+		if !ok {
+			return mismatch()
+		}
+		for i, element := range elements {
+			if err := typeCheckValue(resolved.Type.ValueType, resolved.File, element, valueFile, seen); err != nil {
+				return fmt.Errorf("element %d: %s", i, err)
+			}
+		}
+		return nil
+
+	case StructKind, UnionKind, ExceptionKind:
+		if value == nil {
+			return nil
+		}
+		kvs, ok := value.([]parser.KeyValue)
+		if !ok {
+			return mismatch()
+		}
+		s := resolved.Definition.(*parser.Struct)
+		_, structFile, _ := ResolveDefinition(resolved.Type.Name, resolved.File)
+		if resolved.Kind == UnionKind && len(kvs) > 1 {
+			return fmt.Errorf("union %s can only have one field set", s.Name)
+		}
+		for _, kv := range kvs {
+			name, ok := kv.Key.(string)
+			if !ok {
+				return fmt.Errorf("field names of %s must be strings, got %s", s.Name, describeValue(kv.Key))
+			}
+			var field *parser.Field
+			for _, f := range s.Fields {
+				if f.Name == name {
+					field = f
+				}
+			}
+			if field == nil {
+				return fmt.Errorf("unknown field %q in %s", name, s.Name)
+			}
+			if err := typeCheckValue(field.Type, structFile, kv.Value, valueFile, seen); err != nil {
+				return fmt.Errorf("field %s: %s", name, err)
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// A constant whose value refers back to itself.
+type constantCycleError string
+
+func (c constantCycleError) Error() string {
+	return fmt.Sprintf("constant %s refers to itself", string(c))
+}
+
+func typeCheckEnumValue(resolved *ResolvedType, value *parser.EnumValue, symbol string) error {
+	switch resolved.Kind {
+	case EnumKind:
+		enum := resolved.Definition.(*parser.Enum)
+		if enum.Values[value.Name] != value {
+			return fmt.Errorf("expected a value of enum %s, got %s", typeName(resolved.Type), symbol)
+		}
+		return nil
+	case BuiltinKind:
+		if r, ok := integerRanges[resolved.Type.Name]; ok {
+			if int64(value.Value) < r[0] || int64(value.Value) > r[1] {
+				return fmt.Errorf("%s (%d) is out of range for %s", symbol, value.Value, resolved.Type.Name)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("expected %s, got enum value %s", typeName(resolved.Type), symbol)
+}
+
+// Format a type as it appears in Thrift source, eg. "map<string, list<i32>>".
+func typeName(t *parser.Type) string {
+	switch t.Name {
+	case "map":
+		return fmt.Sprintf("map<%s, %s>", typeName(t.KeyType), typeName(t.ValueType))
+	case "list", "set":
+		return fmt.Sprintf("%s<%s>", t.Name, typeName(t.ValueType))
+	}
+	return t.Name
+}
+
+// Describe a value for error messages.
+func describeValue(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return fmt.Sprintf("string %q", value)
+	case int64:
+		return fmt.Sprintf("integer %d", value)
+	case float64:
+		return fmt.Sprintf("double %v", value)
+	case parser.Identifier:
+		return string(value)
+	case []interface{}:
+		return "list"
+	case []parser.KeyValue, nil:
+		return "map"
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,68 @@
+package thriftlint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypeCheckValue(t *testing.T) {
+	files := parseTestFiles(t, map[string]string{
+		"common.thrift": `
+enum Status { OK = 0; FAILED = 1; }
+typedef i16 Small
+struct User { 1: string name; 2: Status status; }
+const i32 LIMIT = 10
+`,
+		"test.thrift": `
+include "common.thrift"
+enum Local { A = 1; }
+union Choice { 1: i32 a; 2: string b; }
+const i32 I32 = "foo"
+const byte BYTE = 300
+const common.Small SMALL = 40000
+const double DOUBLE = 1
+const bool BOOL = 2
+const list<i32> LIST = [1, "two"]
+const map<string, list<i64>> MAP = {"a": [1], "b": ["c"]}
+const common.User USER = {"name": "bob", "status": common.Status.FAILED}
+const common.User UNKNOWN_FIELD = {"nmae": "bob"}
+const common.User ENUM_FIELD = {"status": 2}
+const common.Status WRONG_ENUM = Local.A
+const i32 FROM_CONST = common.LIMIT
+const string FROM_WRONG_CONST = common.LIMIT
+const Choice CHOICE = {"a": 1, "b": "x"}
+const i32 LOOP = LOOP
+const i32 MISSING = Missing.VALUE
+`,
+	})
+	file := files["/test.thrift"]
+	tests := map[string]string{
+		"I32":              `expected i32, got string "foo"`,
+		"BYTE":             `300 is out of range for byte`,
+		"SMALL":            `40000 is out of range for i16`,
+		"DOUBLE":           ``,
+		"BOOL":             `expected bool, got integer 2`,
+		"LIST":             `element 1: expected i32, got string "two"`,
+		"MAP":              `value of key string "b": element 0: expected i64, got string "c"`,
+		"USER":             ``,
+		"UNKNOWN_FIELD":    `unknown field "nmae" in User`,
+		"ENUM_FIELD":       `field status: 2 is not a value of enum Status`,
+		"WRONG_ENUM":       `expected a value of enum common.Status, got Local.A`,
+		"FROM_CONST":       ``,
+		"FROM_WRONG_CONST": `constant common.LIMIT: expected string, got integer 10`,
+		"CHOICE":           `union Choice can only have one field set`,
+		"LOOP":             `constant LOOP refers to itself`,
+		"MISSING":          ``,
+	}
+	for name, expected := range tests {
+		constant := file.Constants[name]
+		require.NotNil(t, constant, name)
+		err := TypeCheckValue(constant.Type, constant.Value, file)
+		if expected == "" {
+			require.NoError(t, err, name)
+		} else {
+			require.EqualError(t, err, expected, name)
+		}
+	}
+}
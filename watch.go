@@ -0,0 +1,186 @@
+package thriftlint
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/go-thrift/parser"
+)
+
+// Watcher incrementally re-lints a set of sources as files change.
+//
+// Changed files are re-linted along with every file that transitively includes them, and the
+// output lines that were added or removed since the previous run are reported.
+type Watcher struct {
+	linter  *Linter
+	format  func(msg *Message) string
+	sources []string
+	// Absolute path of each included file to the files that include it.
+	includedBy map[string][]string
+	// Formatted messages by absolute path of the file they apply to.
+	messages map[string][]string
+}
+
+// NewWatcher creates a Watcher for sources, formatting each message as a line of output with
+// format. Messages formatted as "" are dropped.
+func NewWatcher(linter *Linter, sources []string, format func(msg *Message) string) (*Watcher, error) {
+	w := &Watcher{
+		linter:     linter,
+		format:     format,
+		includedBy: map[string][]string{},
+		messages:   map[string][]string{},
+	}
+	for _, source := range sources {
+		path, err := filepath.Abs(source)
+		if err != nil {
+			return nil, err
+		}
+		w.sources = append(w.sources, path)
+	}
+	return w, nil
+}
+
+// Dirs returns the directories that should be watched for changes: the include directories and
+// the directories of the sources.
+func (w *Watcher) Dirs() []string {
+	dirs := append([]string{}, w.linter.includeDirs...)
+	for _, source := range w.sources {
+		dirs = append(dirs, filepath.Dir(source))
+	}
+	return dirs
+}
+
+// Lines returns every current output line, sorted.
+func (w *Watcher) Lines() []string {
+	out := []string{}
+	for _, lines := range w.messages {
+		out = append(out, lines...)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Lint the files affected by a set of changed files, given as absolute paths, or all sources if
+// changed is nil. Returns the output lines added and removed by the change.
+func (w *Watcher) Lint(changed []string) (added, removed []string, err error) {
+	previous := w.Lines()
+	// Update the include graph. If parsing fails the previous graph is used.
+	if files, err := ParseWithOverlay(w.linter.includeDirs, w.sources, w.linter.overlay); err == nil {
+		graph := includedBy(files)
+		// Newly included files must be linted too.
+		for path := range graph {
+			if _, ok := w.includedBy[path]; !ok && changed != nil {
+				changed = append(changed, path)
+			}
+		}
+		w.includedBy = graph
+	}
+	// Drop messages for files that are no longer included.
+	for path := range w.messages {
+		if !w.isLinted(path) {
+			delete(w.messages, path)
+		}
+	}
+
+	targets := w.sources
+	affected := map[string]bool{}
+	if changed != nil {
+		targets = []string{}
+		for _, path := range w.affected(changed) {
+			affected[path] = true
+			if _, err := os.Stat(path); err == nil && w.isLinted(path) {
+				targets = append(targets, path)
+			} else {
+				delete(w.messages, path)
+			}
+		}
+	}
+
+	if len(targets) > 0 {
+		messages, err := w.linter.Lint(targets)
+		if err != nil {
+			return nil, nil, err
+		}
+		for path := range affected {
+			delete(w.messages, path)
+		}
+		if changed == nil {
+			w.messages = map[string][]string{}
+		}
+		for _, msg := range messages {
+			path := msg.File.Filename
+			if changed != nil && !affected[path] {
+				continue
+			}
+			if line := w.format(msg); line != "" {
+				w.messages[path] = append(w.messages[path], line)
+			}
+		}
+	}
+	current := w.Lines()
+	return difference(current, previous), difference(previous, current), nil
+}
+
+// Map the absolute path of each included file to the files that include it.
+func includedBy(files map[string]*parser.Thrift) map[string][]string {
+	out := map[string][]string{}
+	for path, file := range files {
+		for _, include := range file.Includes {
+			out[include] = append(out[include], path)
+		}
+	}
+	for _, paths := range out {
+		sort.Strings(paths)
+	}
+	return out
+}
+
+// Returns true if path is a source or is included, directly or indirectly, by a source.
+func (w *Watcher) isLinted(path string) bool {
+	for _, source := range w.sources {
+		if source == path {
+			return true
+		}
+	}
+	return len(w.includedBy[path]) > 0
+}
+
+// The changed files and all files that transitively include them.
+func (w *Watcher) affected(changed []string) []string {
+	seen := map[string]bool{}
+	queue := append([]string{}, changed...)
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		queue = append(queue, w.includedBy[path]...)
+	}
+	out := []string{}
+	for path := range seen {
+		out = append(out, path)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Lines in a that are not in b, each terminated by a newline.
+func difference(a, b []string) []string {
+	counts := map[string]int{}
+	for _, line := range b {
+		counts[line]++
+	}
+	out := []string{}
+	for _, line := range a {
+		if counts[line] > 0 {
+			counts[line]--
+			continue
+		}
+		out = append(out, strings.TrimRight(line, "\n")+"\n")
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package thriftlint
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alecthomas/go-thrift/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "thriftlint-watch")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	common := filepath.Join(dir, "common.thrift")
+	service := filepath.Join(dir, "service.thrift")
+	write := func(path, content string) {
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+	write(common, "struct user {}\n")
+	write(service, "include \"common.thrift\"\nstruct request {}\n")
+
+	check := MakeCheck("naming", func(s *parser.Struct) (messages Messages) {
+		if s.Name[0] >= 'a' && s.Name[0] <= 'z' {
+			messages.Warning(s, "%s should be capitalised", s.Name)
+		}
+		return
+	})
+	linter, err := New([]Check{check}, WithIncludeDirs(dir))
+	require.NoError(t, err)
+	w, err := NewWatcher(linter, []string{service}, func(msg *Message) string {
+		return fmt.Sprintf("%s: %s", filepath.Base(msg.File.Filename), msg.Message)
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{dir, dir}, w.Dirs())
+
+	added, removed, err := w.Lint(nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"common.thrift: user should be capitalised\n",
+		"service.thrift: request should be capitalised\n",
+	}, added)
+	require.Empty(t, removed)
+	require.Equal(t, map[string][]string{common: {service}}, w.includedBy)
+	require.Equal(t, []string{common, service}, w.affected([]string{common}))
+	require.Equal(t, []string{service}, w.affected([]string{service}))
+
+	// A change to an included file re-lints it and the files including it.
+	write(common, "struct User {}\nstruct account {}\n")
+	added, removed, err = w.Lint([]string{common})
+	require.NoError(t, err)
+	require.Equal(t, []string{"common.thrift: account should be capitalised\n"}, added)
+	require.Equal(t, []string{"common.thrift: user should be capitalised\n"}, removed)
+
+	// Files that are no longer included are dropped.
+	write(service, "struct Request {}\n")
+	added, removed, err = w.Lint([]string{service})
+	require.NoError(t, err)
+	require.Empty(t, added)
+	require.Equal(t, []string{
+		"common.thrift: account should be capitalised\n",
+		"service.thrift: request should be capitalised\n",
+	}, removed)
+	require.Empty(t, w.Lines())
+
+	// Newly included files are linted.
+	write(service, "include \"common.thrift\"\nstruct Request {}\n")
+	added, _, err = w.Lint([]string{service})
+	require.NoError(t, err)
+	require.Equal(t, []string{"common.thrift: account should be capitalised\n"}, added)
+
+	// Deleting an included file drops its messages, but linting the files including it fails.
+	require.NoError(t, os.Remove(common))
+	_, _, err = w.Lint([]string{common})
+	require.Error(t, err)
+	require.Empty(t, w.Lines())
+}
+
+func TestDifference(t *testing.T) {
+	a := []string{"a\n", "b", "b\n", "c\n"}
+	b := []string{"b\n", "d\n"}
+	require.Equal(t, []string{"a\n", "b\n", "c\n"}, difference(a, b))
+	require.Equal(t, []string{"d\n"}, difference(b, a))
+	require.Empty(t, difference(b, b))
+}